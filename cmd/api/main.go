@@ -1,29 +1,164 @@
 package main
 
 import (
+	"BigDataForge/internal/auth"
+	"BigDataForge/internal/changefeed"
+	"BigDataForge/internal/config"
 	"BigDataForge/internal/elastic"
+	"BigDataForge/internal/graphqlapi"
+	"BigDataForge/internal/grpcapi"
+	"BigDataForge/internal/health"
+	"BigDataForge/internal/idempotency"
+	"BigDataForge/internal/lifecycle"
+	"BigDataForge/internal/logging"
+	"BigDataForge/internal/outbox"
+	"BigDataForge/internal/rabbitmq"
+	"BigDataForge/internal/ratelimit"
+	"BigDataForge/internal/resources"
 	"BigDataForge/internal/routes"
+	"BigDataForge/internal/schemas"
+	"BigDataForge/internal/services"
 	"BigDataForge/internal/storage"
+	"BigDataForge/internal/tenancy"
+	"BigDataForge/internal/tracing"
+	"context"
 	"log"
-
-	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
 )
 
 func main() {
+	// "api token" mints a dev-mode token instead of starting the server
+	if len(os.Args) > 1 && os.Args[1] == "token" {
+		runTokenCommand(os.Args[2:])
+		return
+	}
+	// "api config print" shows the configuration the server would start with
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		if err := config.RunCommand("api", os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Load the configuration from the config file, env and flags
+	cfg, err := config.Load("api", os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+	logging.Setup(cfg.Logging, os.Stderr)
+
+	// Components stop in reverse order on SIGTERM: servers drain, the outbox
+	// is flushed, then clients are closed
+	manager := lifecycle.New(cfg.Shutdown.Timeout.Duration())
+
+	// Export spans; they are flushed after every other component stopped
+	shutdownTracing, err := tracing.Setup(cfg.Tracing, "api")
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+	manager.OnStop("tracing", shutdownTracing)
+
 	// Set up Redis connection
-	redisClient := storage.NewRedisClient()
+	redisClient := storage.NewRedisClient(cfg.Redis)
+	manager.Close("redis", redisClient.Close)
+
+	// Change feed subscribers block in XREAD, on connections of their own
+	changeFeedClient := storage.NewChangeFeedClient(cfg.Redis)
+	manager.Close("redis change feed", changeFeedClient.Close)
+	changeFeed := services.NewChangeFeedService(changefeed.NewFeed(changeFeedClient), cfg.Redis.ChangeFeedPoolSize, cfg.HTTP.WebSocketOrigins)
 
 	// Set up ElasticSearch connection
-	esFactory := &elastic.Factory{}
+	esFactory := elastic.NewFactory(cfg.Elasticsearch)
+	manager.Close("elasticsearch", esFactory.Close)
+
+	// Compile the embedded plan schemas; uploaded versions are loaded from Redis on demand
+	schemaRegistry, err := schemas.NewRegistry(redisClient)
+	if err != nil {
+		log.Fatalf("Failed to compile schemas: %v", err)
+	}
 
-	// Set up Gin router
-	router := gin.Default()
+	// Register schema-driven resources
+	registry := resources.NewRegistry()
+	if cfg.Resources.SchemaDir != "" {
+		if err := registry.LoadDir(cfg.Resources.SchemaDir); err != nil {
+			log.Fatalf("Failed to load resource schemas: %v", err)
+		}
+	}
+
+	// Build the authenticator chain configured for this deployment
+	authenticator, err := auth.NewChain(redisClient, cfg.Auth)
+	if err != nil {
+		log.Fatalf("Failed to configure authentication: %v", err)
+	}
+
+	// Org memberships come from token claims, the admin API and the configured admins.
+	// Claims of locally minted dev tokens are trusted in dev mode.
+	claimIssuers := cfg.Tenancy.ClaimIssuers
+	if cfg.Auth.DevMode() {
+		claimIssuers = append(claimIssuers, auth.DevIssuer)
+	}
+	resolver := tenancy.NewResolver(tenancy.NewStore(redisClient), cfg.Tenancy.Admins, claimIssuers)
+
+	// Per-caller request limits shared by all replicas through Redis
+	rateLimits, err := ratelimit.NewPolicies(cfg.RateLimits)
+	if err != nil {
+		log.Fatalf("Failed to configure rate limits: %v", err)
+	}
+
+	// Responses replayed to retries carrying the same Idempotency-Key
+	idempotencyStore := idempotency.NewStore(redisClient, cfg.Idempotency.TTL.Duration())
+
+	// Depth and complexity bounds of GraphQL queries
+	graphqlLimits := graphqlapi.Limits{MaxDepth: cfg.GraphQL.MaxDepth, MaxComplexity: cfg.GraphQL.MaxComplexity}
+
+	// Keep a connection to RabbitMQ, declaring the plan exchange and queues after every reconnect
+	rabbitFactory := rabbitmq.NewFactory(cfg.RabbitMQ)
+	rabbitSupervisor := rabbitFactory.NewSupervisor()
+	manager.Run("rabbitmq", rabbitSupervisor.Run)
+
+	// Publish plan change events to RabbitMQ for the listener; once the servers
+	// drained and the relay stopped, the events left in the outbox are flushed
+	relay := outbox.NewRelay(changefeed.NewFeed(redisClient), rabbitSupervisor, rabbitFactory.PlanExchange)
+	manager.OnStop("outbox flush", relay.Flush)
+	manager.Run("outbox relay", relay.Run)
+
+	// Readiness checks; without Elasticsearch or RabbitMQ the API is degraded, not down
+	checker := health.NewChecker()
+	checker.Add("redis", true, health.Redis(redisClient))
+	checker.Add("schemas", true, health.Schemas(schemaRegistry))
+	checker.Add("elasticsearch", false, health.Elasticsearch(esFactory))
+	checker.Add("rabbitmq", false, relay.Check)
+	checker.Add("shutdown", true, health.Stopping(manager.Stopping()))
+
+	// Serve the plan API over gRPC on its own port
+	grpcServer := grpcapi.NewServer(redisClient, esFactory, schemaRegistry, authenticator, resolver, rateLimits, changeFeed)
+	grpcListener, err := net.Listen("tcp", ":"+strconv.Itoa(cfg.GRPC.Port))
+	if err != nil {
+		log.Fatalf("Failed to listen for gRPC: %v", err)
+	}
+	manager.Serve("grpc server", func() error { return grpcServer.Serve(grpcListener) }, func(ctx context.Context) error {
+		return grpcapi.Shutdown(ctx, grpcServer)
+	})
+
+	// Set up Gin router, trusting forwarded client IPs only from the configured proxies
+	router, err := routes.NewRouter(cfg.HTTP.TrustedProxies)
+	if err != nil {
+		log.Fatalf("Failed to configure trusted proxies: %v", err)
+	}
 
 	// Initialize routes
-	routes.SetupRoutes(router, redisClient, esFactory)
+	routes.SetupRoutes(router, redisClient, esFactory, registry, schemaRegistry, authenticator, resolver, rateLimits, idempotencyStore, graphqlLimits, checker, changeFeed)
 
 	// Start the server
-	if err := router.Run(":8080"); err != nil {
-		log.Fatalf("Server failed to start: %v", err)
+	manager.ServeHTTP("http server", &http.Server{Addr: ":" + strconv.Itoa(cfg.HTTP.Port), Handler: router})
+	slog.Info("Serving", "httpPort", cfg.HTTP.Port, "grpcPort", cfg.GRPC.Port)
+
+	if err := manager.Wait(); err != nil {
+		log.Fatalf("Stopped with errors: %v", err)
 	}
+	slog.Info("Shutdown complete")
 }
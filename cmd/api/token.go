@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"BigDataForge/internal/auth"
+	"BigDataForge/internal/config"
+)
+
+// runTokenCommand mints a token for AUTH_MODE=dev, signed with the configured
+// dev key file:
+//
+//	go run ./cmd/api token -sub alice -org example.com -roles editor
+func runTokenCommand(args []string) {
+	flags := flag.NewFlagSet("token", flag.ExitOnError)
+	subject := flags.String("sub", "dev-user", "subject of the token")
+	email := flags.String("email", "", "email claim")
+	org := flags.String("org", "", "org the subject is a member of")
+	roles := flags.String("roles", "viewer", "comma separated roles in the org (viewer, editor, admin)")
+	scopes := flags.String("scopes", "", "comma separated OAuth scopes")
+	ttl := flags.Duration("ttl", 12*time.Hour, "token lifetime")
+	cfg, err := config.LoadFlags(flags, args)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	key, err := auth.LoadOrCreateDevKey(cfg.Auth.DevKeyFile)
+	if err != nil {
+		log.Fatalf("Failed to load dev signing key: %v", err)
+	}
+
+	token, err := auth.MintDevToken(key, auth.DevToken{
+		Subject: *subject,
+		Email:   *email,
+		Org:     *org,
+		Roles:   splitList(*roles),
+		Scopes:  splitList(*scopes),
+		TTL:     *ttl,
+	})
+	if err != nil {
+		log.Fatalf("Failed to sign token: %v", err)
+	}
+	fmt.Fprintln(os.Stdout, token)
+}
+
+// Helper to split a comma separated flag value
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -1,20 +1,55 @@
 package main
 
 import (
+	"BigDataForge/internal/config"
+	"BigDataForge/internal/controllers"
 	"BigDataForge/internal/elastic"
+	"BigDataForge/internal/events"
+	"BigDataForge/internal/health"
+	"BigDataForge/internal/lifecycle"
+	"BigDataForge/internal/logging"
+	"BigDataForge/internal/metrics"
 	"BigDataForge/internal/models"
 	"BigDataForge/internal/rabbitmq"
+	"BigDataForge/internal/requestid"
+	"BigDataForge/internal/storage"
+	"BigDataForge/internal/tracing"
+	"BigDataForge/internal/webhooks"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/elastic/go-elasticsearch/esapi"
-	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const (
+	indexerTag    = "indexer"
+	webhooksTag   = "webhooks"
+	prefetchCount = 10
+
+	// Transient failures are retried after 1s, then 2s, before the message is dead-lettered
+	maxAttempts = 3
+	retryDelay  = time.Second
+)
+
+// errMalformed marks messages that fail the same way however often they are
+// handled, so they are dead-lettered without retries.
+var errMalformed = errors.New("malformed message")
+
 func failOnError(err error, msg string) {
 	if err != nil {
 		log.Fatalf("%s: %s", msg, err)
@@ -22,89 +57,281 @@ func failOnError(err error, msg string) {
 }
 
 func main() {
-	rabbitFactory := rabbitmq.Factory{}
-	elasticFactory := elastic.Factory{}
-
-	// Connect to RabbitMQ
-	conn, err := rabbitFactory.NewConnection()
-	failOnError(err, "Failed to connect to RabbitMQ")
-	defer conn.Close()
-
-	// Create a new RabbitMQ channel
-	ch, err := rabbitFactory.NewChannel(conn)
-	failOnError(err, "Failed to create RabbitMQ channel")
-	defer ch.Close()
-
-	q, err := ch.QueueDeclare(
-		"plan_queue",
-		false,
-		true,
-		false,
-		false,
-		nil,
-	)
-	failOnError(err, "Failed to declare a queue")
-
-	msgs, err := ch.Consume(
-		q.Name,
-		"myConsumer",
-		true,
-		false,
-		false,
-		false,
-		nil,
-	)
-	failOnError(err, "Failed to register a consumer")
+	// "listener config print" shows the configuration the listener would start with
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		if err := config.RunCommand("listener", os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	cfg, err := config.Load("listener", os.Args[1:])
+	failOnError(err, "Failed to load configuration")
+	logging.Setup(cfg.Logging, os.Stderr)
+
+	// Components stop in reverse order on SIGTERM: consuming stops and the
+	// message being indexed is finished before the clients are closed
+	manager := lifecycle.New(cfg.Shutdown.Timeout.Duration())
+
+	// Export spans; they are flushed after every other component stopped
+	shutdownTracing, err := tracing.Setup(cfg.Tracing, "listener")
+	failOnError(err, "Failed to set up tracing")
+	manager.OnStop("tracing", shutdownTracing)
+
+	rabbitFactory := rabbitmq.NewFactory(cfg.RabbitMQ)
+	elasticFactory := elastic.NewFactory(cfg.Elasticsearch)
+
+	// Connect to Redis
+	redisClient := storage.NewRedisClient(cfg.Redis)
+	manager.Close("redis", redisClient.Close)
 
 	// Connect to Elasticsearch
-	esClient, err := elasticFactory.NewClient()
+	esClient, err := elasticFactory.Client()
 	failOnError(err, "Failed to create Elasticsearch client")
+	manager.Close("elasticsearch", elasticFactory.Close)
+
+	// Probes and operators reach the listener through a small admin server; checks are added as components start
+	checker := health.NewChecker()
+	checker.Add("redis", true, health.Redis(redisClient))
+	checker.Add("elasticsearch", true, health.Elasticsearch(elasticFactory))
+	checker.Add("shutdown", true, health.Stopping(manager.Stopping()))
+	manager.ServeHTTP("admin server", &http.Server{Addr: ":" + strconv.Itoa(cfg.Listener.AdminPort), Handler: adminRouter(checker)})
 
 	// Ensure the Elasticsearch index and mapping exist
-	ensureIndex(esClient)
+	ensureIndex(esClient, elasticFactory.PlanIndex)
+
+	// Queue webhook deliveries for plan events and send them in the background
+	dispatcher := webhooks.NewDispatcher(redisClient, webhooks.NewStore(redisClient))
+	manager.Run("webhook worker", webhooks.NewWorker(dispatcher).Run)
+
+	// Consume the plan queues over a supervised connection, which reconnects
+	// and registers the consumers again after the broker restarted. Messages
+	// are acknowledged once handled, so unhandled ones are redelivered, and
+	// those that keep failing go to the queue's dead-letter queue; stopping
+	// cancels the consumers and waits for the messages in flight.
+	rabbitSupervisor := rabbitFactory.NewSupervisor()
+	checker.Add("rabbitmq", true, rabbitSupervisor.Check)
+	consumers := []struct {
+		name, queue, tag string
+		handle           messageHandler
+	}{
+		{"consumer", rabbitFactory.IndexQueue, indexerTag, func(ctx context.Context, d amqp.Delivery) error {
+			return indexMessage(ctx, d, esClient, elasticFactory.PlanIndex)
+		}},
+		{"webhook consumer", rabbitFactory.WebhookQueue, webhooksTag, func(ctx context.Context, d amqp.Delivery) error {
+			return dispatchMessage(ctx, d, dispatcher)
+		}},
+	}
+	for _, consumer := range consumers {
+		queue, handle, stats := consumer.queue, consumer.handle, &consumerStats{}
+		rabbitSupervisor.Consume(rabbitmq.Consumer{
+			Queue:    queue,
+			Tag:      consumer.tag,
+			Prefetch: prefetchCount,
+			Process: func(msgs <-chan amqp.Delivery) {
+				processMessages(msgs, queue, handle, esClient, stats)
+			},
+		})
+		checker.Add(consumer.name, true, stats.check(rabbitSupervisor, queue))
+	}
+	prometheus.MustRegister(metrics.NewQueueCollector(func(queue string) (amqp.Queue, error) {
+		return inspectQueue(rabbitSupervisor, queue)
+	}, rabbitFactory.IndexQueue, rabbitFactory.WebhookQueue,
+		rabbitmq.DeadLetterQueue(rabbitFactory.IndexQueue), rabbitmq.DeadLetterQueue(rabbitFactory.WebhookQueue)))
+	manager.Run("rabbitmq", rabbitSupervisor.Run)
+
+	slog.Info("Listening for messages", "exchange", rabbitFactory.PlanExchange, "adminPort", cfg.Listener.AdminPort)
+	if err := manager.Wait(); err != nil {
+		log.Fatalf("Stopped with errors: %v", err)
+	}
+	slog.Info("Shutdown complete")
+}
 
-	// Start message processing
-	go processMessages(msgs, esClient)
+// adminRouter serves the health endpoints and metrics of the listener
+func adminRouter(checker *health.Checker) *gin.Engine {
+	healthController := controllers.NewHealthController(checker)
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.GET("/healthz", healthController.Live)
+	router.GET("/readyz", healthController.Ready)
+	router.GET("/status", healthController.Status)
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	return router
+}
 
-	log.Println("Listening for messages. Press CTRL+C to exit.")
-	select {}
+// messageHandler handles one message of a queue.
+type messageHandler func(ctx context.Context, d amqp.Delivery) error
 
+// processMessages handles the messages of a queue and acknowledges those it
+// handled. Failures other than malformed messages are retried, and messages
+// that still fail are rejected without requeueing them, which dead-letters
+// them. While the Elasticsearch circuit breaker is open, messages are requeued
+// instead, and consuming pauses until it lets a request through.
+func processMessages(msgs <-chan amqp.Delivery, queue string, handle messageHandler, esClient *elastic.Client, stats *consumerStats) {
+	for d := range msgs {
+		metrics.MessagesConsumed.WithLabelValues(queue).Inc()
+		ctx := requestid.NewContext(context.Background(), d.CorrelationId)
+		ctx, span := tracing.Tracer().Start(tracing.ExtractHeaders(ctx, d.Headers), queue+" process",
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(
+				attribute.String("messaging.system", "rabbitmq"),
+				attribute.String("messaging.destination.name", queue),
+				attribute.String("messaging.message.id", d.MessageId),
+			))
+		err := handleWithRetries(ctx, d, handle)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		stats.record(err)
+		if errors.Is(err, elastic.ErrUnavailable) {
+			slog.WarnContext(ctx, "Elasticsearch is unavailable, requeueing message", "messageId", d.MessageId)
+			if err := d.Nack(false, true); err != nil {
+				slog.ErrorContext(ctx, "Failed to requeue message", "messageId", d.MessageId, "error", err)
+			}
+			time.Sleep(esClient.RetryAfter())
+			continue
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to handle message", "messageId", d.MessageId, "error", err)
+			if err := d.Nack(false, false); err != nil {
+				slog.ErrorContext(ctx, "Failed to reject message", "messageId", d.MessageId, "error", err)
+				continue
+			}
+			metrics.MessagesDeadLettered.WithLabelValues(queue).Inc()
+			continue
+		}
+		if err := d.Ack(false); err != nil {
+			slog.ErrorContext(ctx, "Failed to acknowledge message", "messageId", d.MessageId, "error", err)
+			continue
+		}
+		metrics.MessagesAcked.WithLabelValues(queue).Inc()
+	}
 }
 
-func processMessages(msgs <-chan amqp.Delivery, esClient *elasticsearch.Client) {
-	for d := range msgs {
-		log.Printf("Received message: %s", d.Body)
+// Helper to handle a message, retrying transient failures such as Redis or
+// Elasticsearch errors with a growing delay
+func handleWithRetries(ctx context.Context, d amqp.Delivery, handle messageHandler) error {
+	delay := retryDelay
+	for attempt := 1; ; attempt++ {
+		err := handle(ctx, d)
+		if err == nil || attempt == maxAttempts || errors.Is(err, errMalformed) || errors.Is(err, elastic.ErrUnavailable) {
+			return err
+		}
+		slog.WarnContext(ctx, "Failed to handle message, retrying", "messageId", d.MessageId, "attempt", attempt, "retryIn", delay.String(), "error", err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
 
+// indexMessage updates the index for one plan event.
+func indexMessage(ctx context.Context, d amqp.Delivery, esClient *elastic.Client, index string) error {
+	event, err := events.FromDelivery(d)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errMalformed, err)
+	}
+	slog.DebugContext(ctx, "Received message", "messageId", d.MessageId, "type", event.Type, "planId", event.PlanID,
+		"document", logging.Payload(logging.PlanRules, event.Document))
+	if event.Type == "delete" {
+		if err := deletePlan(ctx, esClient, index, event.PlanID); err != nil {
+			return fmt.Errorf("failed to delete Plan from index: %w", err)
+		}
+	} else {
 		var plan models.Plan
-		if err := json.Unmarshal(d.Body, &plan); err != nil {
-			log.Printf("Failed to deserialize Plan: %s", err)
-			continue
+		if err := json.Unmarshal(event.Document, &plan); err != nil {
+			return fmt.Errorf("%w: failed to deserialize Plan: %v", errMalformed, err)
+		}
+
+		// Updates may drop child objects, so reindex the whole tree
+		if event.Type != "create" {
+			if err := deletePlan(ctx, esClient, index, plan.ObjectID); err != nil {
+				slog.WarnContext(ctx, "Failed to delete previous Plan documents", "planId", plan.ObjectID, "error", err)
+			}
 		}
 
 		// Index the plan and its related documents
-		if err := indexPlan(esClient, plan); err != nil {
-			log.Printf("Failed to index Plan: %s", err)
+		if err := indexPlan(ctx, esClient, index, plan); err != nil {
+			return fmt.Errorf("failed to index Plan: %w", err)
 		}
 	}
+	return nil
+}
+
+// dispatchMessage queues the webhook deliveries of one plan event. Only events
+// relayed from the API carry an ID that subscribers can deduplicate on.
+func dispatchMessage(ctx context.Context, d amqp.Delivery, dispatcher *webhooks.Dispatcher) error {
+	event, err := events.FromDelivery(d)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errMalformed, err)
+	}
+	if event.ID == "" {
+		return nil
+	}
+	if err := dispatcher.Dispatch(ctx, event); err != nil {
+		return fmt.Errorf("failed to queue webhook deliveries: %w", err)
+	}
+	return nil
 }
 
-func indexPlan(esClient *elasticsearch.Client, plan models.Plan) error {
+// deletePlan removes a plan and every child and grandchild document from the index
+func deletePlan(ctx context.Context, esClient *elastic.Client, index, planID string) error {
+	planQuery := map[string]interface{}{"ids": map[string]interface{}{"values": []string{planID}}}
+	childOf := func(parentType string, query map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"has_parent": map[string]interface{}{"parent_type": parentType, "query": query}}
+	}
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should": []interface{}{
+					planQuery,
+					childOf("plan", planQuery),
+					childOf("linkedPlanServices", childOf("plan", planQuery)),
+				},
+			},
+		},
+	}
+	queryJSON, err := json.Marshal(query)
+	if err != nil {
+		return err
+	}
+
+	refresh := true
+	req := esapi.DeleteByQueryRequest{
+		Index:   []string{index},
+		Body:    bytes.NewReader(queryJSON),
+		Refresh: &refresh,
+	}
+	res, err := req.Do(ctx, esClient)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("failed to delete documents of Plan ID=%s: %s", planID, res.String())
+	}
+	slog.InfoContext(ctx, "Plan documents deleted", "planId", planID)
+	return nil
+}
+
+func indexPlan(ctx context.Context, esClient *elastic.Client, index string, plan models.Plan) error {
 	// Index the main plan
 	plan.PlanJoin = map[string]interface{}{"name": "plan"}
-	if err := indexDocument(esClient, "plans", plan.ObjectID, plan, ""); err != nil {
+	if err := indexDocument(ctx, esClient, index, plan.ObjectID, plan, ""); err != nil {
 		return err
 	}
 
 	// Index PlanCostShares
 	plan.PlanCostShares.PlanJoin = map[string]interface{}{"name": "planCostShares", "parent": plan.ObjectID}
-	if err := indexDocument(esClient, "plans", plan.PlanCostShares.ObjectID, plan.PlanCostShares, plan.ObjectID); err != nil {
+	if err := indexDocument(ctx, esClient, index, plan.PlanCostShares.ObjectID, plan.PlanCostShares, plan.ObjectID); err != nil {
 		return err
 	}
 
 	// Index LinkedPlanServices and related documents
 	for _, linkedPlanService := range plan.LinkedPlanServices {
 		linkedPlanService.PlanJoin = map[string]interface{}{"name": "linkedPlanServices", "parent": plan.ObjectID}
-		if err := indexDocument(esClient, "plans", linkedPlanService.ObjectID, linkedPlanService, plan.ObjectID); err != nil {
+		if err := indexDocument(ctx, esClient, index, linkedPlanService.ObjectID, linkedPlanService, plan.ObjectID); err != nil {
 			return err
 		}
 
@@ -113,7 +340,7 @@ func indexPlan(esClient *elasticsearch.Client, plan models.Plan) error {
 			"name":   "linkedService",
 			"parent": linkedPlanService.ObjectID,
 		}
-		if err := indexDocument(esClient, "plans", linkedPlanService.LinkedService.ObjectID, linkedPlanService.LinkedService, linkedPlanService.ObjectID); err != nil {
+		if err := indexDocument(ctx, esClient, index, linkedPlanService.LinkedService.ObjectID, linkedPlanService.LinkedService, linkedPlanService.ObjectID); err != nil {
 			return err
 		}
 
@@ -122,14 +349,16 @@ func indexPlan(esClient *elasticsearch.Client, plan models.Plan) error {
 			"name":   "planServiceCostShares",
 			"parent": linkedPlanService.ObjectID,
 		}
-		if err := indexDocument(esClient, "plans", linkedPlanService.PlanserviceCostShares.ObjectID, linkedPlanService.PlanserviceCostShares, linkedPlanService.ObjectID); err != nil {
+		if err := indexDocument(ctx, esClient, index, linkedPlanService.PlanserviceCostShares.ObjectID, linkedPlanService.PlanserviceCostShares, linkedPlanService.ObjectID); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func indexDocument(esClient *elasticsearch.Client, indexName, documentID string, document interface{}, routing string) error {
+// indexDocument indexes one document and records the request's duration, or
+// the type of error Elasticsearch rejected the document with.
+func indexDocument(ctx context.Context, esClient *elastic.Client, indexName, documentID string, document interface{}, routing string) error {
 	docJSON, err := json.Marshal(document)
 	if err != nil {
 		return err
@@ -143,54 +372,59 @@ func indexDocument(esClient *elasticsearch.Client, indexName, documentID string,
 		Routing:    routing,
 	}
 
-	res, err := req.Do(context.Background(), esClient)
+	started := time.Now()
+	res, err := req.Do(ctx, esClient)
+	metrics.IndexRequestDuration.WithLabelValues(indexName).Observe(time.Since(started).Seconds())
 	if err != nil {
 		return err
 	}
 	defer res.Body.Close()
 
 	if res.IsError() {
-		log.Printf("Error indexing document ID=%s: %s", documentID, res.String())
-		return fmt.Errorf("failed to index document ID=%s", documentID)
+		var rejection struct {
+			Error struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		}
+		errorType := "unknown"
+		if json.NewDecoder(res.Body).Decode(&rejection) == nil && rejection.Error.Type != "" {
+			errorType = rejection.Error.Type
+		}
+		metrics.IndexFailures.WithLabelValues(indexName, errorType).Inc()
+		slog.ErrorContext(ctx, "Failed to index document", "index", indexName, "documentId", documentID, "errorType", errorType, "reason", rejection.Error.Reason)
+		return fmt.Errorf("failed to index document ID=%s: %s", documentID, res.Status())
 	}
 
-	log.Printf("Document ID=%s indexed successfully", documentID)
+	slog.InfoContext(ctx, "Document indexed", "index", indexName, "documentId", documentID)
 	return nil
 }
 
-func ensureIndex(esClient *elasticsearch.Client) {
+func ensureIndex(esClient *elastic.Client, index string) {
 	// Create the index if it doesn't exist
 
-	fmt.Println("Creating index")
-	req := esapi.IndicesCreateRequest{Index: "plans"}
+	slog.Debug("Creating index", "index", index)
+	req := esapi.IndicesCreateRequest{Index: index}
 	res, err := req.Do(context.Background(), esClient)
 	if err != nil {
 		log.Fatalf("Failed to create index: %s", err)
 	}
 	defer res.Body.Close()
 
-	fmt.Println("Index created")
-
 	if res.IsError() {
-		log.Printf("Index creation response: %s", res.String())
+		slog.Info("Index not created", "index", index, "response", res.String())
 	}
 
-	fmt.Println("Applying mapping")
-
 	// Apply the mapping
 	mapping := getMapping()
 
-	fmt.Println("Mapping created")
-
 	mappingJSON, err := json.Marshal(mapping)
 	if err != nil {
 		log.Fatalf("Failed to serialize mapping: %s", err)
 	}
 
-	fmt.Println("Mapping serialized")
-
 	req2 := esapi.IndicesPutMappingRequest{
-		Index: []string{"plans"},
+		Index: []string{index},
 		Body:  bytes.NewReader(mappingJSON),
 	}
 	res2, err := req2.Do(context.Background(), esClient)
@@ -200,9 +434,9 @@ func ensureIndex(esClient *elasticsearch.Client) {
 	defer res2.Body.Close()
 
 	if res2.IsError() {
-		log.Printf("Mapping application response: %s", res2.String())
+		slog.Warn("Failed to apply mapping", "index", index, "response", res2.String())
 	} else {
-		log.Println("Mapping applied successfully")
+		slog.Info("Mapping applied", "index", index)
 	}
 }
 func getMapping() map[string]interface{} {
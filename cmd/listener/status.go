@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"BigDataForge/internal/health"
+	"BigDataForge/internal/rabbitmq"
+
+	"github.com/streadway/amqp"
+)
+
+// consumerStats records the progress of message processing for the admin server.
+type consumerStats struct {
+	handled     atomic.Int64
+	failed      atomic.Int64
+	lastHandled atomic.Int64 // Unix time in milliseconds of the last message handled without error
+}
+
+func (stats *consumerStats) record(err error) {
+	if err != nil {
+		stats.failed.Add(1)
+		return
+	}
+	stats.handled.Add(1)
+	stats.lastHandled.Store(time.Now().UnixMilli())
+}
+
+// check reports the processing counters and the messages waiting in the queue.
+func (stats *consumerStats) check(supervisor *rabbitmq.Supervisor, queue string) health.Check {
+	return func(ctx context.Context) (map[string]interface{}, error) {
+		details := map[string]interface{}{
+			"handled": stats.handled.Load(),
+			"failed":  stats.failed.Load(),
+		}
+		if lastHandled := stats.lastHandled.Load(); lastHandled != 0 {
+			details["lastHandledAt"] = time.UnixMilli(lastHandled)
+		}
+
+		q, err := inspectQueue(supervisor, queue)
+		if err != nil {
+			return details, err
+		}
+		details["queueLag"] = q.Messages
+		details["consumers"] = q.Consumers
+		if q.Consumers == 0 {
+			return details, errors.New("no consumer on " + queue)
+		}
+		return details, nil
+	}
+}
+
+// inspectQueue passively declares the queue on its own channel: a failed
+// inspection closes the channel it runs on.
+func inspectQueue(supervisor *rabbitmq.Supervisor, queue string) (amqp.Queue, error) {
+	ch, err := supervisor.Channel()
+	if err != nil {
+		return amqp.Queue{}, err
+	}
+	defer ch.Close()
+	return ch.QueueInspect(queue)
+}
@@ -0,0 +1,193 @@
+package audit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"BigDataForge/internal/auth"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Plan mutations are appended to the Redis stream "audit:plans". Entries are
+// never trimmed or rewritten; the stream ID orders them and encodes the time.
+
+const stream = "audit:plans"
+
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionPatch  = "patch"
+	ActionDelete = "delete"
+)
+
+// Entry records one mutation of a plan.
+type Entry struct {
+	ID           string    `json:"id"`
+	Timestamp    time.Time `json:"timestamp"`
+	Action       string    `json:"action"`
+	PlanID       string    `json:"planId"`
+	Org          string    `json:"org,omitempty"`
+	Actor        string    `json:"actor"` // auth.Principal.ID of the caller
+	ActorSubject string    `json:"actorSubject"`
+	ActorEmail   string    `json:"actorEmail,omitempty"`
+	AuthMethod   string    `json:"authMethod,omitempty"`
+	ETagBefore   string    `json:"etagBefore,omitempty"`
+	ETagAfter    string    `json:"etagAfter,omitempty"`
+	RequestID    string    `json:"requestId,omitempty"`
+	ClientIP     string    `json:"clientIp,omitempty"`
+}
+
+// Origin is where a mutation was requested from.
+type Origin struct {
+	RequestID string
+	ClientIP  string
+}
+
+type originContextKey struct{}
+
+// NewContext returns a copy of ctx carrying the origin of the request.
+func NewContext(ctx context.Context, origin Origin) context.Context {
+	return context.WithValue(ctx, originContextKey{}, origin)
+}
+
+// FromContext starts an entry with the principal and origin carried by ctx.
+func FromContext(ctx context.Context, action, planID string) Entry {
+	origin, _ := ctx.Value(originContextKey{}).(Origin)
+	entry := Entry{
+		Timestamp: time.Now().UTC(),
+		Action:    action,
+		PlanID:    planID,
+		RequestID: origin.RequestID,
+		ClientIP:  origin.ClientIP,
+	}
+	if principal := auth.FromContext(ctx); principal != nil {
+		entry.Actor = principal.ID()
+		entry.ActorSubject = principal.Subject
+		entry.ActorEmail = principal.Email
+		entry.AuthMethod = principal.Method
+	}
+	return entry
+}
+
+// Log reads and appends audit entries.
+type Log struct {
+	redisClient *redis.Client
+}
+
+func NewLog(redisClient *redis.Client) *Log {
+	return &Log{redisClient: redisClient}
+}
+
+// Append queues the entry on pipe, typically the transaction that performs the
+// mutation, so the change and its audit entry are written together.
+func (l *Log) Append(ctx context.Context, pipe redis.Cmdable, entry Entry) {
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		Values: map[string]interface{}{
+			"timestamp":    entry.Timestamp.Format(time.RFC3339Nano),
+			"action":       entry.Action,
+			"planId":       entry.PlanID,
+			"org":          entry.Org,
+			"actor":        entry.Actor,
+			"actorSubject": entry.ActorSubject,
+			"actorEmail":   entry.ActorEmail,
+			"authMethod":   entry.AuthMethod,
+			"etagBefore":   entry.ETagBefore,
+			"etagAfter":    entry.ETagAfter,
+			"requestId":    entry.RequestID,
+			"clientIp":     entry.ClientIP,
+		},
+	})
+}
+
+// Filter selects audit entries. Zero values match everything.
+type Filter struct {
+	PlanID string
+	Actor  string // principal ID (<method>:<subject>) or email
+	From   time.Time
+	To     time.Time
+	After  string // only entries after this entry ID, for paging
+}
+
+func (f Filter) matches(entry Entry) bool {
+	if f.PlanID != "" && entry.PlanID != f.PlanID {
+		return false
+	}
+	if f.Actor != "" && entry.Actor != f.Actor && entry.ActorEmail != f.Actor {
+		return false
+	}
+	return true
+}
+
+const scanBatchSize = 500
+
+// Scan calls fn for each matching entry in chronological order until fn returns false.
+func (l *Log) Scan(ctx context.Context, filter Filter, fn func(Entry) bool) error {
+	start, end := "-", "+"
+	if !filter.From.IsZero() {
+		start = strconv.FormatInt(filter.From.UnixMilli(), 10)
+	}
+	if filter.After != "" {
+		start = "(" + filter.After
+	}
+	if !filter.To.IsZero() {
+		end = strconv.FormatInt(filter.To.UnixMilli(), 10)
+	}
+
+	for {
+		messages, err := l.redisClient.XRangeN(ctx, stream, start, end, scanBatchSize).Result()
+		if err != nil {
+			return err
+		}
+		for _, message := range messages {
+			entry := entryFromMessage(message)
+			if filter.matches(entry) && !fn(entry) {
+				return nil
+			}
+		}
+		if len(messages) < scanBatchSize {
+			return nil
+		}
+		start = "(" + messages[len(messages)-1].ID
+	}
+}
+
+// Query returns up to limit matching entries.
+func (l *Log) Query(ctx context.Context, filter Filter, limit int) ([]Entry, error) {
+	entries := []Entry{}
+	err := l.Scan(ctx, filter, func(entry Entry) bool {
+		entries = append(entries, entry)
+		return len(entries) < limit
+	})
+	return entries, err
+}
+
+func entryFromMessage(message redis.XMessage) Entry {
+	field := func(name string) string {
+		value, _ := message.Values[name].(string)
+		return value
+	}
+	timestamp, _ := time.Parse(time.RFC3339Nano, field("timestamp"))
+	// Entries written before the actor field was recorded derive it from the subject
+	actor := field("actor")
+	if actor == "" && field("actorSubject") != "" {
+		actor = field("authMethod") + ":" + field("actorSubject")
+	}
+	return Entry{
+		ID:           message.ID,
+		Timestamp:    timestamp,
+		Action:       field("action"),
+		PlanID:       field("planId"),
+		Org:          field("org"),
+		Actor:        actor,
+		ActorSubject: field("actorSubject"),
+		ActorEmail:   field("actorEmail"),
+		AuthMethod:   field("authMethod"),
+		ETagBefore:   field("etagBefore"),
+		ETagAfter:    field("etagAfter"),
+		RequestID:    field("requestId"),
+		ClientIP:     field("clientIp"),
+	}
+}
@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// API keys are never stored in clear text. Redis layout:
+//
+//	apikey:<sha256 of key>  hash with id, subject, scopes and createdAt
+//	apikeys                 hash of key id -> sha256 of key, used for listing and revocation
+
+const apiKeyPrefix = "bdf_"
+
+var ErrUnknownAPIKey = errors.New("unknown API key")
+
+// APIKey describes a stored API key without its secret.
+type APIKey struct {
+	ID        string   `json:"id"`
+	Subject   string   `json:"subject"`
+	Scopes    []string `json:"scopes"`
+	CreatedAt string   `json:"createdAt"`
+}
+
+// APIKeyStore keeps hashed API keys in Redis.
+type APIKeyStore struct {
+	redisClient *redis.Client
+}
+
+func NewAPIKeyStore(redisClient *redis.Client) *APIKeyStore {
+	return &APIKeyStore{redisClient: redisClient}
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Create generates a new API key. The key itself is only returned here.
+func (s *APIKeyStore) Create(ctx context.Context, subject string, scopes []string) (*APIKey, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	key := apiKeyPrefix + hex.EncodeToString(secret)
+	hash := hashAPIKey(key)
+
+	record := &APIKey{
+		ID:        hash[:16],
+		Subject:   subject,
+		Scopes:    scopes,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	_, err := s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, "apikey:"+hash, map[string]interface{}{
+			"id":        record.ID,
+			"subject":   record.Subject,
+			"scopes":    strings.Join(record.Scopes, " "),
+			"createdAt": record.CreatedAt,
+		})
+		pipe.HSet(ctx, "apikeys", record.ID, hash)
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return record, key, nil
+}
+
+// Lookup returns the record of a presented API key.
+func (s *APIKeyStore) Lookup(ctx context.Context, key string) (*APIKey, error) {
+	fields, err := s.redisClient.HGetAll(ctx, "apikey:"+hashAPIKey(key)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, ErrUnknownAPIKey
+	}
+	return apiKeyFromFields(fields), nil
+}
+
+// List returns every stored API key.
+func (s *APIKeyStore) List(ctx context.Context) ([]APIKey, error) {
+	hashes, err := s.redisClient.HVals(ctx, "apikeys").Result()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]APIKey, 0, len(hashes))
+	for _, hash := range hashes {
+		fields, err := s.redisClient.HGetAll(ctx, "apikey:"+hash).Result()
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) > 0 {
+			keys = append(keys, *apiKeyFromFields(fields))
+		}
+	}
+	return keys, nil
+}
+
+// Revoke deletes an API key by its ID.
+func (s *APIKeyStore) Revoke(ctx context.Context, id string) error {
+	hash, err := s.redisClient.HGet(ctx, "apikeys", id).Result()
+	if err == redis.Nil {
+		return ErrUnknownAPIKey
+	}
+	if err != nil {
+		return err
+	}
+	_, err = s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, "apikey:"+hash)
+		pipe.HDel(ctx, "apikeys", id)
+		return nil
+	})
+	return err
+}
+
+func apiKeyFromFields(fields map[string]string) *APIKey {
+	return &APIKey{
+		ID:        fields["id"],
+		Subject:   fields["subject"],
+		Scopes:    strings.Fields(fields["scopes"]),
+		CreatedAt: fields["createdAt"],
+	}
+}
+
+// APIKeyAuthenticator accepts keys sent as "X-API-Key: <key>" or "Authorization: ApiKey <key>".
+type APIKeyAuthenticator struct {
+	Store *APIKeyStore
+}
+
+func NewAPIKeyAuthenticator(store *APIKeyStore) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{Store: store}
+}
+
+func (a *APIKeyAuthenticator) Name() string { return "apikey" }
+
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get("X-API-Key")
+	if key == "" {
+		authHeader := r.Header.Get("Authorization")
+		if trimmed := strings.TrimPrefix(authHeader, "ApiKey "); trimmed != authHeader {
+			key = trimmed
+		}
+	}
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+
+	record, err := a.Store.Lookup(r.Context(), key)
+	if errors.Is(err, ErrUnknownAPIKey) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Principal{
+		Subject:    record.Subject,
+		Method:     a.Name(),
+		Scopes:     record.Scopes,
+		Restricted: true,
+		Claims: map[string]interface{}{
+			"sub":   record.Subject,
+			"scope": strings.Join(record.Scopes, " "),
+			"kid":   record.ID,
+		},
+	}, nil
+}
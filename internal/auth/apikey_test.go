@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestAPIKeyAuthenticate(t *testing.T) {
+	server := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+	store := NewAPIKeyStore(redisClient)
+	authenticator := NewAPIKeyAuthenticator(store)
+
+	record, key, err := store.Create(context.Background(), "reporting", []string{ScopeRead})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		header  string
+		value   string
+		wantErr error
+	}{
+		{"X-API-Key", "X-API-Key", key, nil},
+		{"Authorization", "Authorization", "ApiKey " + key, nil},
+		{"no key", "Authorization", "Bearer " + key, ErrNoCredentials},
+		{"unknown key", "X-API-Key", apiKeyPrefix + "unknown", ErrInvalidCredentials},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/plans", nil)
+		r.Header.Set(tc.header, tc.value)
+		principal, err := authenticator.Authenticate(r)
+		if !errors.Is(err, tc.wantErr) || (tc.wantErr == nil && err != nil) {
+			t.Errorf("%s: error = %v, want %v", tc.name, err, tc.wantErr)
+			continue
+		}
+		if tc.wantErr == nil && (principal.Subject != "reporting" || !principal.Restricted || !principal.HasScope(ScopeRead) || principal.HasScope(ScopeWrite)) {
+			t.Errorf("%s: principal = %+v", tc.name, principal)
+		}
+	}
+
+	// The key is only stored hashed
+	if server.Exists("apikey:" + key) {
+		t.Error("the API key is stored in clear text")
+	}
+
+	if err := store.Revoke(context.Background(), record.ID); err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/plans", nil)
+	r.Header.Set("X-API-Key", key)
+	if _, err := authenticator.Authenticate(r); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("revoked key: error = %v, want ErrInvalidCredentials", err)
+	}
+}
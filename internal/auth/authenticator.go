@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// PrincipalKey stores the authenticated principal in the gin context.
+const PrincipalKey = "principal"
+
+var (
+	// ErrNoCredentials means the request carries no credentials for an
+	// authenticator, so the next authenticator in the chain is tried.
+	ErrNoCredentials = errors.New("no credentials")
+
+	// ErrInvalidCredentials means credentials were present but rejected.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Scopes limit what restricted principals may do, whatever roles they hold:
+// ScopeRead allows reading and searching, ScopeWrite also allows changes, and
+// ScopeAdmin allows administration.
+const (
+	ScopeRead  = "plans:read"
+	ScopeWrite = "plans:write"
+	ScopeAdmin = "admin"
+)
+
+// Principal is the authenticated caller of a request. Restricted principals,
+// such as API keys or tokens carrying a scope claim, may only use their
+// Scopes; the others are only limited by their roles.
+type Principal struct {
+	Subject    string
+	Email      string
+	Method     string
+	Scopes     []string
+	Restricted bool
+	Claims     map[string]interface{}
+}
+
+// ID qualifies the subject with the auth method, so that equal subjects
+// issued by different authenticators stay apart.
+func (p *Principal) ID() string {
+	return p.Method + ":" + p.Subject
+}
+
+// HasScope reports whether the principal was granted a scope.
+func (p *Principal) HasScope(scope string) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type principalContextKey struct{}
+
+// NewContext returns a copy of ctx carrying the principal.
+func NewContext(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// FromContext returns the principal of ctx, which may also be a gin context
+// holding it under PrincipalKey, or nil.
+func FromContext(ctx context.Context) *Principal {
+	if principal, ok := ctx.Value(principalContextKey{}).(*Principal); ok {
+		return principal
+	}
+	if principal, ok := ctx.Value(PrincipalKey).(*Principal); ok {
+		return principal
+	}
+	return nil
+}
+
+// Authenticator verifies one kind of credential. It returns ErrNoCredentials
+// when the request does not carry that kind of credential.
+type Authenticator interface {
+	Name() string
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// Chain tries each authenticator in order until one recognises the request's credentials.
+type Chain []Authenticator
+
+func (chain Chain) Name() string {
+	names := make([]string, 0, len(chain))
+	for _, authenticator := range chain {
+		names = append(names, authenticator.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+func (chain Chain) Authenticate(r *http.Request) (*Principal, error) {
+	for _, authenticator := range chain {
+		principal, err := authenticator.Authenticate(r)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		return principal, err
+	}
+	return nil, ErrNoCredentials
+}
+
+// bearerToken extracts the token of an "Authorization: Bearer" header.
+func bearerToken(r *http.Request) (string, bool) {
+	authHeader := r.Header.Get("Authorization")
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	if authHeader == "" || token == authHeader {
+		return "", false
+	}
+	return token, true
+}
+
+// scopesFromClaims reads OAuth scopes from the "scope" (space separated) or "scp" claim.
+func scopesFromClaims(claims map[string]interface{}) []string {
+	switch scopes := claims["scope"].(type) {
+	case string:
+		return strings.Fields(scopes)
+	}
+	switch scopes := claims["scp"].(type) {
+	case string:
+		return strings.Fields(scopes)
+	case []interface{}:
+		result := make([]string, 0, len(scopes))
+		for _, scope := range scopes {
+			if s, ok := scope.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// hasScopeClaim reports whether a token carries a "scope" or "scp" claim, in
+// which case its principal is restricted to the scopes it lists.
+func hasScopeClaim(claims map[string]interface{}) bool {
+	_, scope := claims["scope"]
+	_, scp := claims["scp"]
+	return scope || scp
+}
+
+// tokenIssuer reads the unverified "iss" claim of a JWT so bearer token
+// authenticators can skip tokens minted by other issuers.
+func tokenIssuer(token string) string {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+	var claims struct {
+		Issuer string `json:"iss"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return ""
+	}
+	return claims.Issuer
+}
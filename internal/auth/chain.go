@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"BigDataForge/internal/config"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// NewChain builds the authenticator chain for this deployment. The methods
+// are tried in the configured order (default "google", or "dev,apikey,hmac"
+// in dev mode):
+//
+//	google  Google ID tokens for the Google client ID
+//	oidc    JWTs from the OIDC issuer for the OIDC audience
+//	apikey  hashed API keys stored in Redis
+//	hmac    HMAC-signed requests with secrets stored in Redis
+//	dev     tokens signed with the local key in the dev key file; only allowed in dev mode
+func NewChain(redisClient *redis.Client, cfg config.Auth) (Chain, error) {
+	var chain Chain
+	for _, method := range cfg.Methods {
+		switch method {
+		case config.AuthGoogle:
+			chain = append(chain, NewGoogleAuthenticator(cfg.GoogleClientID))
+		case config.AuthOIDC:
+			if cfg.OIDCIssuer == "" {
+				return nil, errors.New("auth method oidc requires an OIDC issuer")
+			}
+			// Without an audience, tokens the issuer minted for any other client would be accepted
+			if cfg.OIDCAudience == "" {
+				return nil, errors.New("auth method oidc requires an OIDC audience")
+			}
+			chain = append(chain, NewOIDCAuthenticator(cfg.OIDCIssuer, cfg.OIDCAudience))
+		case config.AuthAPIKey:
+			chain = append(chain, NewAPIKeyAuthenticator(NewAPIKeyStore(redisClient)))
+		case config.AuthHMAC:
+			chain = append(chain, NewHMACAuthenticator(NewHMACKeyStore(redisClient)))
+		case config.AuthDev:
+			if !cfg.DevMode() {
+				return nil, errors.New("auth method dev requires AUTH_MODE=dev")
+			}
+			key, err := LoadOrCreateDevKey(cfg.DevKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load dev signing key: %w", err)
+			}
+			slog.Warn("Dev authentication enabled, trusting tokens signed with the local key", "keyFile", cfg.DevKeyFile)
+			chain = append(chain, NewDevAuthenticator(&key.PublicKey))
+		default:
+			return nil, fmt.Errorf("unknown auth method %q", method)
+		}
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("no auth methods enabled")
+	}
+	return chain, nil
+}
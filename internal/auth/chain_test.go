@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"BigDataForge/internal/config"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// stubAuthenticator returns a fixed result and counts its calls.
+type stubAuthenticator struct {
+	name      string
+	principal *Principal
+	err       error
+	calls     int
+}
+
+func (s *stubAuthenticator) Name() string { return s.name }
+
+func (s *stubAuthenticator) Authenticate(*http.Request) (*Principal, error) {
+	s.calls++
+	return s.principal, s.err
+}
+
+func TestChainAuthenticate(t *testing.T) {
+	alice := &Principal{Subject: "alice", Method: "second"}
+
+	for _, tc := range []struct {
+		name      string
+		first     error
+		second    error
+		want      *Principal
+		wantErr   error
+		wantCalls int // calls of the second authenticator
+	}{
+		{"first has no credentials", ErrNoCredentials, nil, alice, nil, 1},
+		{"first rejects credentials", ErrInvalidCredentials, nil, nil, ErrInvalidCredentials, 0},
+		{"nobody has credentials", ErrNoCredentials, ErrNoCredentials, nil, ErrNoCredentials, 1},
+	} {
+		first := &stubAuthenticator{name: "first", err: tc.first}
+		second := &stubAuthenticator{name: "second", principal: alice, err: tc.second}
+		if tc.second != nil {
+			second.principal = nil
+		}
+
+		principal, err := Chain{first, second}.Authenticate(httptest.NewRequest(http.MethodGet, "/", nil))
+		if !errors.Is(err, tc.wantErr) || (tc.wantErr == nil && err != nil) || principal != tc.want {
+			t.Errorf("%s: Authenticate = %v, %v, want %v, %v", tc.name, principal, err, tc.want, tc.wantErr)
+		}
+		if second.calls != tc.wantCalls {
+			t.Errorf("%s: second authenticator called %d times, want %d", tc.name, second.calls, tc.wantCalls)
+		}
+	}
+}
+
+func TestNewChain(t *testing.T) {
+	// Building the chain does not connect to Redis
+	redisClient := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+
+	for _, tc := range []struct {
+		name    string
+		cfg     config.Auth
+		wantErr string
+	}{
+		{"oidc", config.Auth{Methods: []string{"oidc"}, OIDCIssuer: "https://issuer.example.com", OIDCAudience: "bdf-api"}, ""},
+		{"oidc without issuer", config.Auth{Methods: []string{"oidc"}, OIDCAudience: "bdf-api"}, "OIDC issuer"},
+		{"oidc without audience", config.Auth{Methods: []string{"oidc"}, OIDCIssuer: "https://issuer.example.com"}, "OIDC audience"},
+		{"dev outside dev mode", config.Auth{Methods: []string{"dev"}}, "AUTH_MODE=dev"},
+		{"unknown method", config.Auth{Methods: []string{"basic"}}, "unknown auth method"},
+		{"no methods", config.Auth{}, "no auth methods"},
+	} {
+		chain, err := NewChain(redisClient, tc.cfg)
+		switch {
+		case tc.wantErr == "" && err != nil:
+			t.Errorf("%s: NewChain failed: %v", tc.name, err)
+		case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+			t.Errorf("%s: NewChain = %v, %v, want an error about %s", tc.name, chain.Name(), err, tc.wantErr)
+		}
+	}
+}
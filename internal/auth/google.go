@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	"google.golang.org/api/idtoken"
+)
+
+var googleIssuers = map[string]bool{
+	"accounts.google.com":         true,
+	"https://accounts.google.com": true,
+}
+
+// GoogleAuthenticator validates Google ID tokens issued for the configured client.
+type GoogleAuthenticator struct {
+	ClientID string
+}
+
+func NewGoogleAuthenticator(clientID string) *GoogleAuthenticator {
+	return &GoogleAuthenticator{ClientID: clientID}
+}
+
+func (a *GoogleAuthenticator) Name() string { return "google" }
+
+func (a *GoogleAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token, ok := bearerToken(r)
+	if !ok || !googleIssuers[tokenIssuer(token)] {
+		return nil, ErrNoCredentials
+	}
+
+	payload, err := idtoken.Validate(r.Context(), token, a.ClientID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	email, _ := payload.Claims["email"].(string)
+	return &Principal{
+		Subject:    payload.Subject,
+		Email:      email,
+		Method:     a.Name(),
+		Scopes:     scopesFromClaims(payload.Claims),
+		Restricted: hasScopeClaim(payload.Claims),
+		Claims:     payload.Claims,
+	}, nil
+}
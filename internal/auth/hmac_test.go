@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+// Helper to create an HMAC authenticator on miniredis with one signing key
+func newTestHMAC(t *testing.T) (*HMACAuthenticator, *HMACKey, string) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+
+	store := NewHMACKeyStore(redisClient)
+	key, secret, err := store.Create(context.Background(), "billing", []string{ScopeRead})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewHMACAuthenticator(store), key, secret
+}
+
+// signedRequest describes how a test request is signed.
+type signedRequest struct {
+	keyID      string
+	secret     string
+	age        time.Duration // how long ago the request was signed
+	signedURI  string        // URI covered by the signature, if not the request's
+	signedBody string        // body covered by the signature, if not the request's
+}
+
+func (s signedRequest) build(method, uri, body string) *http.Request {
+	timestamp := strconv.FormatInt(time.Now().Add(-s.age).Unix(), 10)
+	signedURI, signedBody := uri, body
+	if s.signedURI != "" {
+		signedURI = s.signedURI
+	}
+	if s.signedBody != "" {
+		signedBody = s.signedBody
+	}
+	signature := SignRequest(s.secret, method, signedURI, timestamp, []byte(signedBody))
+
+	r := httptest.NewRequest(method, uri, strings.NewReader(body))
+	r.Header.Set("Authorization", hmacScheme+"KeyId="+s.keyID+",Signature="+signature)
+	r.Header.Set(hmacTimestampHeader, timestamp)
+	return r
+}
+
+func TestHMACAuthenticate(t *testing.T) {
+	authenticator, key, secret := newTestHMAC(t)
+	const uri, body = "/api/v1/plans?org=a.com", `{"objectId":"p1"}`
+
+	for _, tc := range []struct {
+		name    string
+		request signedRequest
+		wantErr error
+	}{
+		{"valid", signedRequest{keyID: key.ID, secret: secret}, nil},
+		{"slightly old", signedRequest{keyID: key.ID, secret: secret, age: hmacMaxSkew - time.Minute}, nil},
+		{"slightly ahead", signedRequest{keyID: key.ID, secret: secret, age: -hmacMaxSkew + time.Minute}, nil},
+		{"too old", signedRequest{keyID: key.ID, secret: secret, age: hmacMaxSkew + time.Minute}, ErrInvalidCredentials},
+		{"too far ahead", signedRequest{keyID: key.ID, secret: secret, age: -hmacMaxSkew - time.Minute}, ErrInvalidCredentials},
+		{"wrong secret", signedRequest{keyID: key.ID, secret: "not-the-secret"}, ErrInvalidCredentials},
+		{"unknown key", signedRequest{keyID: "unknown", secret: secret}, ErrInvalidCredentials},
+		{"other URI", signedRequest{keyID: key.ID, secret: secret, signedURI: "/api/v1/plans?org=b.com"}, ErrInvalidCredentials},
+		{"other body", signedRequest{keyID: key.ID, secret: secret, signedBody: `{"objectId":"p2"}`}, ErrInvalidCredentials},
+	} {
+		r := tc.request.build(http.MethodPost, uri, body)
+		principal, err := authenticator.Authenticate(r)
+		if !errors.Is(err, tc.wantErr) || (tc.wantErr == nil && err != nil) {
+			t.Errorf("%s: error = %v, want %v", tc.name, err, tc.wantErr)
+			continue
+		}
+		if tc.wantErr != nil {
+			continue
+		}
+		if principal.Subject != "billing" || principal.Method != "hmac" || !principal.Restricted || !principal.HasScope(ScopeRead) {
+			t.Errorf("%s: principal = %+v", tc.name, principal)
+		}
+		// Handlers still read the body
+		if read, _ := io.ReadAll(r.Body); string(read) != body {
+			t.Errorf("%s: body after authentication = %q, want %q", tc.name, read, body)
+		}
+	}
+}
+
+func TestHMACRejectsReplay(t *testing.T) {
+	authenticator, key, secret := newTestHMAC(t)
+	request := signedRequest{keyID: key.ID, secret: secret}
+	first := request.build(http.MethodDelete, "/api/v1/plans/p1", "")
+
+	if _, err := authenticator.Authenticate(first); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	replay := httptest.NewRequest(http.MethodDelete, "/api/v1/plans/p1", nil)
+	replay.Header = first.Header.Clone()
+	if _, err := authenticator.Authenticate(replay); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("replayed request: error = %v, want ErrInvalidCredentials", err)
+	}
+}
+
+func TestHMACMalformedHeaders(t *testing.T) {
+	authenticator, key, _ := newTestHMAC(t)
+
+	for _, tc := range []struct {
+		name          string
+		authorization string
+		timestamp     string
+		wantErr       error
+	}{
+		{"other scheme", "Bearer token", "", ErrNoCredentials},
+		{"missing signature", hmacScheme + "KeyId=" + key.ID, strconv.FormatInt(time.Now().Unix(), 10), ErrInvalidCredentials},
+		{"missing timestamp", hmacScheme + "KeyId=" + key.ID + ",Signature=abc", "", ErrInvalidCredentials},
+		{"invalid timestamp", hmacScheme + "KeyId=" + key.ID + ",Signature=abc", "yesterday", ErrInvalidCredentials},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/plans", nil)
+		r.Header.Set("Authorization", tc.authorization)
+		if tc.timestamp != "" {
+			r.Header.Set(hmacTimestampHeader, tc.timestamp)
+		}
+		if _, err := authenticator.Authenticate(r); !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: error = %v, want %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestHMACLimitsBody(t *testing.T) {
+	authenticator, key, secret := newTestHMAC(t)
+	body := strings.Repeat("x", hmacMaxBody+1)
+	r := signedRequest{keyID: key.ID, secret: secret}.build(http.MethodPost, "/api/v1/plans", body)
+
+	if _, err := authenticator.Authenticate(r); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("oversized body: error = %v, want ErrInvalidCredentials", err)
+	}
+}
@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	jwksCacheTTL = time.Hour
+	// Unknown key IDs trigger a refresh, but not more often than this
+	jwksMinRefreshInterval = time.Minute
+)
+
+// OIDCAuthenticator validates JWTs from any OpenID Connect issuer that were
+// issued for Audience. The JWKS location comes from the issuer's discovery
+// document and keys are cached.
+type OIDCAuthenticator struct {
+	Issuer     string
+	Audience   string
+	HTTPClient *http.Client
+
+	mu          sync.RWMutex
+	jwksURI     string
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	refreshedAt time.Time
+}
+
+func NewOIDCAuthenticator(issuer, audience string) *OIDCAuthenticator {
+	return &OIDCAuthenticator{
+		Issuer:     strings.TrimSuffix(issuer, "/"),
+		Audience:   audience,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (a *OIDCAuthenticator) Name() string { return "oidc" }
+
+func (a *OIDCAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token, ok := bearerToken(r)
+	if !ok || strings.TrimSuffix(tokenIssuer(token), "/") != a.Issuer {
+		return nil, ErrNoCredentials
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return a.key(r.Context(), kid)
+	}, jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "PS256"}),
+		jwt.WithExpirationRequired(), jwt.WithAudience(a.Audience))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", ErrInvalidCredentials)
+	}
+	email, _ := claims["email"].(string)
+	return &Principal{
+		Subject:    subject,
+		Email:      email,
+		Method:     a.Name(),
+		Scopes:     scopesFromClaims(claims),
+		Restricted: hasScopeClaim(claims),
+		Claims:     claims,
+	}, nil
+}
+
+// key returns the public key for a key ID, refreshing the JWKS when the cache
+// is stale or the key is unknown.
+func (a *OIDCAuthenticator) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	a.mu.RLock()
+	key, found := a.keys[kid]
+	fresh := time.Since(a.fetchedAt) < jwksCacheTTL
+	canRefresh := time.Since(a.refreshedAt) > jwksMinRefreshInterval
+	a.mu.RUnlock()
+
+	if found && fresh {
+		return key, nil
+	}
+	if !fresh || canRefresh {
+		if err := a.refresh(ctx); err != nil {
+			if found {
+				// Keep serving the cached key while the issuer is unreachable
+				return key, nil
+			}
+			return nil, err
+		}
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if key, ok := a.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (a *OIDCAuthenticator) refresh(ctx context.Context) error {
+	a.mu.Lock()
+	a.refreshedAt = time.Now()
+	jwksURI := a.jwksURI
+	a.mu.Unlock()
+
+	if jwksURI == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := a.getJSON(ctx, a.Issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+			return fmt.Errorf("oidc discovery: %w", err)
+		}
+		if strings.TrimSuffix(discovery.Issuer, "/") != a.Issuer || discovery.JWKSURI == "" {
+			return errors.New("oidc discovery: issuer mismatch or missing jwks_uri")
+		}
+		jwksURI = discovery.JWKSURI
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := a.getJSON(ctx, jwksURI, &set); err != nil {
+		return fmt.Errorf("oidc jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if key, err := k.publicKey(); err == nil {
+			keys[k.Kid] = key
+		}
+	}
+
+	a.mu.Lock()
+	a.jwksURI = jwksURI
+	a.keys = keys
+	a.fetchedAt = time.Now()
+	a.mu.Unlock()
+	return nil
+}
+
+func (a *OIDCAuthenticator) getJSON(ctx context.Context, url string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, res.Status)
+	}
+	return json.NewDecoder(res.Body).Decode(target)
+}
+
+// jwk is a JSON Web Key as published in a JWKS document.
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// testIssuer is an OIDC provider serving discovery and a JWKS with one RSA key.
+type testIssuer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer := &testIssuer{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"issuer": issuer.URL, "jwks_uri": issuer.URL + "/jwks"})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kid": "k1",
+			"kty": "RSA",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+	return issuer
+}
+
+// Helper to sign claims with key under kid
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestOIDCAuthenticate(t *testing.T) {
+	issuer := newTestIssuer(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator := NewOIDCAuthenticator(issuer.URL, "bdf-api")
+
+	// Helper to build the claims of a valid token with changes applied
+	claims := func(changes jwt.MapClaims) jwt.MapClaims {
+		base := jwt.MapClaims{
+			"iss": issuer.URL,
+			"aud": "bdf-api",
+			"sub": "alice",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}
+		for name, value := range changes {
+			if value == nil {
+				delete(base, name)
+			} else {
+				base[name] = value
+			}
+		}
+		return base
+	}
+
+	for _, tc := range []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{"valid", signToken(t, issuer.key, "k1", claims(nil)), nil},
+		{"audience list", signToken(t, issuer.key, "k1", claims(jwt.MapClaims{"aud": []string{"other", "bdf-api"}})), nil},
+		{"wrong issuer", signToken(t, issuer.key, "k1", claims(jwt.MapClaims{"iss": "https://accounts.example.com"})), ErrNoCredentials},
+		{"wrong audience", signToken(t, issuer.key, "k1", claims(jwt.MapClaims{"aud": "other-client"})), ErrInvalidCredentials},
+		{"no audience", signToken(t, issuer.key, "k1", claims(jwt.MapClaims{"aud": nil})), ErrInvalidCredentials},
+		{"no subject", signToken(t, issuer.key, "k1", claims(jwt.MapClaims{"sub": nil})), ErrInvalidCredentials},
+		{"empty subject", signToken(t, issuer.key, "k1", claims(jwt.MapClaims{"sub": ""})), ErrInvalidCredentials},
+		{"expired", signToken(t, issuer.key, "k1", claims(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()})), ErrInvalidCredentials},
+		{"no expiry", signToken(t, issuer.key, "k1", claims(jwt.MapClaims{"exp": nil})), ErrInvalidCredentials},
+		{"other signing key", signToken(t, otherKey, "k1", claims(nil)), ErrInvalidCredentials},
+		{"unknown key ID", signToken(t, otherKey, "k2", claims(nil)), ErrInvalidCredentials},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/plans", nil)
+		r.Header.Set("Authorization", "Bearer "+tc.token)
+		principal, err := authenticator.Authenticate(r)
+		if !errors.Is(err, tc.wantErr) || (tc.wantErr == nil && err != nil) {
+			t.Errorf("%s: error = %v, want %v", tc.name, err, tc.wantErr)
+			continue
+		}
+		if tc.wantErr == nil && (principal.Subject != "alice" || principal.Method != "oidc" || principal.Restricted) {
+			t.Errorf("%s: principal = %+v", tc.name, principal)
+		}
+	}
+}
+
+func TestOIDCScopesRestrictPrincipal(t *testing.T) {
+	issuer := newTestIssuer(t)
+	authenticator := NewOIDCAuthenticator(issuer.URL+"/", "bdf-api")
+	token := signToken(t, issuer.key, "k1", jwt.MapClaims{
+		"iss":   issuer.URL,
+		"aud":   "bdf-api",
+		"sub":   "alice",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "openid " + ScopeRead,
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/plans", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	principal, err := authenticator.Authenticate(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !principal.Restricted || !principal.HasScope(ScopeRead) || principal.HasScope(ScopeWrite) {
+		t.Errorf("principal = %+v, want restricted to %s", principal, ScopeRead)
+	}
+}
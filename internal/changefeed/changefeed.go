@@ -0,0 +1,258 @@
+package changefeed
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"BigDataForge/internal/tracing"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Plan changes are appended to the Redis stream "changes:plans" in the same
+// transaction as the write. Unlike the audit log the feed is trimmed to
+// roughly maxLength events; clients resume from the stream ID of the last
+// event they received. Trimming never drops events a consumer group has not
+// acknowledged yet, so the stream grows beyond maxLength while they wait.
+// "changes:plans:trimmed" holds the ID of the newest event trimmed so far, so
+// clients resuming from before it learn that they missed events.
+
+const (
+	stream     = "changes:plans"
+	trimmedKey = "changes:plans:trimmed"
+	maxLength  = 100000
+	trimBatch  = 1000 // most events dropped by one Trim
+)
+
+// Event describes one change of a plan. Document is the plan after the change
+// and is empty for deletes. RequestID and Trace identify the write for logs
+// and traces downstream; they are not shown to clients.
+type Event struct {
+	ID        string            `json:"id"`
+	Type      string            `json:"type"`
+	PlanID    string            `json:"planId"`
+	Org       string            `json:"org"`
+	ETag      string            `json:"etag,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+	Document  json.RawMessage   `json:"document,omitempty"`
+	RequestID string            `json:"-"`
+	Trace     map[string]string `json:"-"`
+}
+
+// Feed appends and reads plan change events.
+type Feed struct {
+	redisClient *redis.Client
+}
+
+func NewFeed(redisClient *redis.Client) *Feed {
+	return &Feed{redisClient: redisClient}
+}
+
+// Append queues the event on pipe, typically the transaction that performs the
+// change, with the trace context of ctx.
+func (f *Feed) Append(ctx context.Context, pipe redis.Cmdable, event Event) {
+	trace, _ := json.Marshal(tracing.Inject(ctx))
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		Values: map[string]interface{}{
+			"type":      event.Type,
+			"planId":    event.PlanID,
+			"org":       event.Org,
+			"etag":      event.ETag,
+			"timestamp": event.Timestamp.Format(time.RFC3339Nano),
+			"document":  string(event.Document),
+			"requestId": event.RequestID,
+			"trace":     string(trace),
+		},
+	})
+}
+
+// LatestID returns the ID of the newest event, so a new subscriber only
+// receives events appended after it connected.
+func (f *Feed) LatestID(ctx context.Context) (string, error) {
+	messages, err := f.redisClient.XRevRangeN(ctx, stream, "+", "-", 1).Result()
+	if err != nil {
+		return "", err
+	}
+	if len(messages) == 0 {
+		return "0-0", nil
+	}
+	return messages[0].ID, nil
+}
+
+// Read returns up to count events after lastID, waiting up to block for new
+// ones. It returns no events and no error when block elapses.
+func (f *Feed) Read(ctx context.Context, lastID string, count int64, block time.Duration) ([]Event, error) {
+	streams, err := f.redisClient.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{stream, lastID},
+		Count:   count,
+		Block:   block,
+	}).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, s := range streams {
+		for _, message := range s.Messages {
+			events = append(events, eventFromMessage(message))
+		}
+	}
+	return events, nil
+}
+
+func eventFromMessage(message redis.XMessage) Event {
+	field := func(name string) string {
+		value, _ := message.Values[name].(string)
+		return value
+	}
+	timestamp, _ := time.Parse(time.RFC3339Nano, field("timestamp"))
+	event := Event{
+		ID:        message.ID,
+		Type:      field("type"),
+		PlanID:    field("planId"),
+		Org:       field("org"),
+		ETag:      field("etag"),
+		Timestamp: timestamp,
+		RequestID: field("requestId"),
+	}
+	if document := field("document"); document != "" {
+		event.Document = json.RawMessage(document)
+	}
+	if trace := field("trace"); trace != "" {
+		_ = json.Unmarshal([]byte(trace), &event.Trace)
+	}
+	return event
+}
+
+// EnsureGroup creates a consumer group that starts with events appended from
+// now on; an existing group keeps its position.
+func (f *Feed) EnsureGroup(ctx context.Context, group string) error {
+	err := f.redisClient.XGroupCreateMkStream(ctx, stream, group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+// ReadGroup returns events for a group consumer. With pending set it returns
+// events delivered to the consumer earlier but never acknowledged, otherwise
+// new events, waiting up to block for them.
+func (f *Feed) ReadGroup(ctx context.Context, group, consumer string, pending bool, count int64, block time.Duration) ([]Event, error) {
+	id := ">"
+	if pending {
+		// Pending entries are returned right away; a zero Block would wait forever
+		id, block = "0", -1
+	}
+	streams, err := f.redisClient.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{stream, id},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, s := range streams {
+		for _, message := range s.Messages {
+			events = append(events, eventFromMessage(message))
+		}
+	}
+	return events, nil
+}
+
+// Ack marks events as processed by a group.
+func (f *Feed) Ack(ctx context.Context, group string, ids ...string) error {
+	return f.redisClient.XAck(ctx, stream, group, ids...).Err()
+}
+
+// Trim drops up to trimBatch of the oldest events beyond maxLength. ackedID is
+// an event the group acknowledged, so every earlier event was delivered to it;
+// trimming stops at ackedID or at the oldest event still pending in the group,
+// whichever comes first.
+func (f *Feed) Trim(ctx context.Context, group, ackedID string) error {
+	length, err := f.redisClient.XLen(ctx, stream).Result()
+	if err != nil {
+		return err
+	}
+	excess := length - maxLength
+	if excess <= 0 {
+		return nil
+	}
+	if excess > trimBatch {
+		excess = trimBatch
+	}
+
+	pending, err := f.redisClient.XPending(ctx, stream, group).Result()
+	if err != nil {
+		return err
+	}
+	keep := ackedID
+	if pending.Count > 0 && compareIDs(pending.Lower, keep) < 0 {
+		keep = pending.Lower
+	}
+
+	// The event after the excess is the oldest one maxLength keeps
+	messages, err := f.redisClient.XRangeN(ctx, stream, "-", "+", excess+1).Result()
+	if err != nil || len(messages) == 0 {
+		return err
+	}
+	if oldest := messages[len(messages)-1].ID; compareIDs(oldest, keep) < 0 {
+		keep = oldest
+	}
+
+	// The newest event the trim drops
+	dropped, err := f.redisClient.XRevRangeN(ctx, stream, "("+keep, "-", 1).Result()
+	if err != nil || len(dropped) == 0 {
+		return err
+	}
+	_, err = f.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XTrimMinID(ctx, stream, keep)
+		pipe.Set(ctx, trimmedKey, dropped[0].ID, 0)
+		return nil
+	})
+	return err
+}
+
+// Expired reports whether events appended after lastID were trimmed, so a
+// client resuming from lastID would silently miss them.
+func (f *Feed) Expired(ctx context.Context, lastID string) (bool, error) {
+	trimmed, err := f.redisClient.Get(ctx, trimmedKey).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return compareIDs(trimmed, lastID) > 0, nil
+}
+
+// Helper to order stream IDs ("<milliseconds>-<sequence>")
+func compareIDs(a, b string) int {
+	aMs, aSeq := splitID(a)
+	bMs, bSeq := splitID(b)
+	if c := cmp.Compare(aMs, bMs); c != 0 {
+		return c
+	}
+	return cmp.Compare(aSeq, bSeq)
+}
+
+func splitID(id string) (ms, seq uint64) {
+	msPart, seqPart, _ := strings.Cut(id, "-")
+	ms, _ = strconv.ParseUint(msPart, 10, 64)
+	seq, _ = strconv.ParseUint(seqPart, 10, 64)
+	return ms, seq
+}
@@ -0,0 +1,140 @@
+package changefeed
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestCompareIDs(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"1-0", "1-0", 0},
+		{"1-0", "1-1", -1},
+		{"2-0", "1-9", 1},
+		{"9-0", "10-0", -1},
+	} {
+		if got := compareIDs(tc.a, tc.b); got != tc.want {
+			t.Errorf("compareIDs(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+// Helper to fill the stream with count events. IDs have one event per
+// millisecond, since miniredis orders IDs as strings
+func appendEvents(t *testing.T, redisClient *redis.Client, count int) {
+	t.Helper()
+	ctx := context.Background()
+	pipe := redisClient.Pipeline()
+	for i := 0; i < count; i++ {
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: stream,
+			ID:     fmt.Sprintf("%d-0", 1_000_000_000_000+i),
+			Values: map[string]interface{}{"type": "create", "planId": "p", "org": "a.com"},
+		})
+		if i%10000 == 9999 || i == count-1 {
+			if _, err := pipe.Exec(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestTrimKeepsUnacknowledgedEvents(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+	feed := NewFeed(redisClient)
+
+	if err := feed.EnsureGroup(ctx, "outbox"); err != nil {
+		t.Fatal(err)
+	}
+	appendEvents(t, redisClient, maxLength+50)
+
+	// The relay received 30 events and acknowledged the first 20 of them; the
+	// other 20 events beyond maxLength were never delivered
+	delivered, err := feed.ReadGroup(ctx, "outbox", "relay", false, 30, -1)
+	if err != nil || len(delivered) != 30 {
+		t.Fatalf("ReadGroup = %d events, %v", len(delivered), err)
+	}
+	ids := make([]string, 0, 20)
+	for _, event := range delivered[:20] {
+		ids = append(ids, event.ID)
+	}
+	if err := feed.Ack(ctx, "outbox", ids...); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := feed.Trim(ctx, "outbox", ids[len(ids)-1]); err != nil {
+		t.Fatal(err)
+	}
+	oldest, err := redisClient.XRangeN(ctx, stream, "-", "+", 1).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oldest[0].ID != delivered[19].ID {
+		t.Errorf("oldest event after trim = %s, want the last acknowledged event %s", oldest[0].ID, delivered[19].ID)
+	}
+
+	// Once everything is acknowledged the stream shrinks to maxLength
+	rest := make([]string, 0, 10)
+	for _, event := range delivered[20:] {
+		rest = append(rest, event.ID)
+	}
+	if err := feed.Ack(ctx, "outbox", rest...); err != nil {
+		t.Fatal(err)
+	}
+	latest, err := feed.LatestID(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := feed.Trim(ctx, "outbox", latest); err != nil {
+		t.Fatal(err)
+	}
+	if length := redisClient.XLen(ctx, stream).Val(); length != maxLength {
+		t.Errorf("stream length after trim = %d, want %d", length, maxLength)
+	}
+}
+
+func TestExpired(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+	feed := NewFeed(redisClient)
+
+	appendEvents(t, redisClient, maxLength+5)
+	id := func(i int) string { return fmt.Sprintf("%d-0", 1_000_000_000_000+i) }
+	if expired, err := feed.Expired(ctx, "0"); err != nil || expired {
+		t.Errorf("Expired before any trim = %v, %v, want false", expired, err)
+	}
+
+	// The group starts after the existing events, so nothing is pending
+	if err := feed.EnsureGroup(ctx, "outbox"); err != nil {
+		t.Fatal(err)
+	}
+	if err := feed.Trim(ctx, "outbox", id(maxLength+4)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		lastID  string
+		expired bool
+	}{
+		{"0", true},
+		{id(3), true}, // the event after it was trimmed
+		{id(4), false},
+		{id(5), false},
+		{id(maxLength + 4), false},
+	} {
+		if expired, err := feed.Expired(ctx, tc.lastID); err != nil || expired != tc.expired {
+			t.Errorf("Expired(%s) = %v, %v, want %v", tc.lastID, expired, err, tc.expired)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the configuration file when the -config flag is not given.
+const FileEnv = "CONFIG_FILE"
+
+// Load reads the configuration of the named binary. args are its command line
+// arguments without the program name.
+func Load(name string, args []string) (*Config, error) {
+	return LoadFlags(flag.NewFlagSet(name, flag.ContinueOnError), args)
+}
+
+// LoadFlags registers a flag for every setting, plus -config, on fs, parses
+// args with it and loads the configuration. Commands use it to combine their
+// own flags with the configuration flags. Settings are taken from, in
+// increasing precedence: Default, the YAML (.yaml, .yml) or TOML (.toml) file,
+// environment variables and flags.
+func LoadFlags(fs *flag.FlagSet, args []string) (*Config, error) {
+	cfg := Default()
+
+	file := fs.String("config", os.Getenv(FileEnv), "YAML or TOML configuration file (env "+FileEnv+")")
+	flagFields := map[string]reflect.Value{}
+	eachSetting(cfg, func(field reflect.StructField, value reflect.Value) {
+		name := field.Tag.Get("flag")
+		fs.String(name, formatValue(value), field.Tag.Get("usage")+" (env "+field.Tag.Get("env")+")")
+		flagFields[name] = value
+	})
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *file != "" {
+		if err := loadFile(cfg, *file); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	eachSetting(cfg, func(field reflect.StructField, value reflect.Value) {
+		env := field.Tag.Get("env")
+		if raw, ok := os.LookupEnv(env); ok && raw != "" {
+			if err := parseValue(value, raw); err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s: %w", env, err))
+			}
+		}
+	})
+	fs.Visit(func(f *flag.Flag) {
+		if value, ok := flagFields[f.Name]; ok {
+			if err := parseValue(value, f.Value.String()); err != nil {
+				errs = append(errs, fmt.Errorf("invalid -%s: %w", f.Name, err))
+			}
+		}
+	})
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	cfg.resolve()
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, nil
+}
+
+// Helper to decode a configuration file over the defaults; unknown keys are errors
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(cfg)
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	case ".toml":
+		decoder := toml.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(cfg)
+		var strict *toml.StrictMissingError
+		if errors.As(err, &strict) {
+			err = errors.New(strict.String())
+		}
+	default:
+		return fmt.Errorf("configuration file %s must be .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// Helper to visit every setting, that is every field with an env tag
+func eachSetting(cfg *Config, visit func(field reflect.StructField, value reflect.Value)) {
+	sections := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < sections.NumField(); i++ {
+		section := sections.Field(i)
+		for j := 0; j < section.NumField(); j++ {
+			field := section.Type().Field(j)
+			if field.Tag.Get("env") != "" {
+				visit(field, section.Field(j))
+			}
+		}
+	}
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// Helper to set a setting from its env or flag representation. Lists are comma
+// separated.
+func parseValue(value reflect.Value, raw string) error {
+	if value.Addr().Type().Implements(textUnmarshalerType) {
+		return value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Int:
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", raw)
+		}
+		value.SetInt(int64(parsed))
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", value.Type())
+	}
+	return nil
+}
+
+// Helper to format a setting the way parseValue reads it
+func formatValue(value reflect.Value) string {
+	if value.Kind() == reflect.Slice {
+		return strings.Join(value.Interface().([]string), ",")
+	}
+	return fmt.Sprint(value.Interface())
+}
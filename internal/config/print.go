@@ -0,0 +1,96 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"reflect"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "REDACTED"
+
+// Redacted returns a copy of the configuration with secrets hidden: passwords
+// are replaced and URLs keep everything but their password.
+func (c *Config) Redacted() *Config {
+	copied := *c
+	eachSetting(&copied, func(field reflect.StructField, value reflect.Value) {
+		secret := field.Tag.Get("secret")
+		switch {
+		case secret == "":
+		case value.Kind() == reflect.String:
+			value.SetString(redact(secret, value.String()))
+		case value.Kind() == reflect.Slice:
+			// Copy the list, as the copied configuration shares it with c
+			items := make([]string, value.Len())
+			for i, item := range value.Interface().([]string) {
+				items[i] = redact(secret, item)
+			}
+			value.Set(reflect.ValueOf(items))
+		}
+	})
+	return &copied
+}
+
+// Helper to hide a secret value
+func redact(secret, value string) string {
+	if value == "" {
+		return value
+	}
+	if secret == "url" {
+		if parsed, err := url.Parse(value); err == nil {
+			return parsed.Redacted()
+		}
+	}
+	return redacted
+}
+
+// Write encodes the configuration in the file format ("yaml" or "toml"), so
+// that the output can be used as a configuration file.
+func (c *Config) Write(w io.Writer, format string) error {
+	var buf bytes.Buffer
+	switch format {
+	case "yaml":
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(c); err != nil {
+			return err
+		}
+	case "toml":
+		if err := toml.NewEncoder(&buf).Encode(c); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown format %q, use yaml or toml", format)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// RunCommand runs the "config" command of the named binary:
+//
+//	<name> config print [-redacted] [-format yaml|toml] [configuration flags]
+//
+// prints the configuration the binary would start with.
+func RunCommand(name string, args []string) error {
+	if len(args) == 0 || args[0] != "print" {
+		return fmt.Errorf("usage: %s config print [-redacted] [-format yaml|toml] [flags]", name)
+	}
+
+	fs := flag.NewFlagSet(name+" config print", flag.ContinueOnError)
+	redact := fs.Bool("redacted", false, "hide passwords and URL credentials")
+	format := fs.String("format", "yaml", "output format, yaml or toml")
+	cfg, err := LoadFlags(fs, args[1:])
+	if err != nil {
+		return err
+	}
+	if *redact {
+		cfg = cfg.Redacted()
+	}
+	return cfg.Write(os.Stdout, *format)
+}
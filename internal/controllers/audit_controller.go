@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"BigDataForge/internal/audit"
+	"BigDataForge/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+type AuditController struct {
+	Service *services.AuditService
+}
+
+func NewAuditController(redisClient *redis.Client) *AuditController {
+	return &AuditController{
+		Service: services.NewAuditService(audit.NewLog(redisClient)),
+	}
+}
+
+func (controller *AuditController) QueryAuditLog(c *gin.Context) {
+	controller.Service.QueryAuditLog(c)
+}
+
+func (controller *AuditController) ExportAuditLog(c *gin.Context) {
+	controller.Service.ExportAuditLog(c)
+}
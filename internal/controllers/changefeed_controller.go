@@ -0,0 +1,22 @@
+package controllers
+
+import (
+	"BigDataForge/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ChangeFeedController struct {
+	Service *services.ChangeFeedService
+}
+
+// NewChangeFeedController serves the change feed service shared with the gRPC API
+func NewChangeFeedController(service *services.ChangeFeedService) *ChangeFeedController {
+	return &ChangeFeedController{
+		Service: service,
+	}
+}
+
+func (controller *ChangeFeedController) StreamChanges(c *gin.Context) {
+	controller.Service.StreamChanges(c)
+}
@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"BigDataForge/internal/auth"
+	"BigDataForge/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+type CredentialController struct {
+	Service *services.CredentialService
+}
+
+func NewCredentialController(redisClient *redis.Client) *CredentialController {
+	return &CredentialController{
+		Service: services.NewCredentialService(auth.NewAPIKeyStore(redisClient), auth.NewHMACKeyStore(redisClient)),
+	}
+}
+
+func (controller *CredentialController) CreateAPIKey(c *gin.Context) {
+	controller.Service.CreateAPIKey(c)
+}
+
+func (controller *CredentialController) ListAPIKeys(c *gin.Context) {
+	controller.Service.ListAPIKeys(c)
+}
+
+func (controller *CredentialController) RevokeAPIKey(c *gin.Context) {
+	controller.Service.RevokeAPIKey(c)
+}
+
+func (controller *CredentialController) CreateHMACKey(c *gin.Context) {
+	controller.Service.CreateHMACKey(c)
+}
+
+func (controller *CredentialController) RevokeHMACKey(c *gin.Context) {
+	controller.Service.RevokeHMACKey(c)
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+
+	"BigDataForge/internal/elastic"
+	"BigDataForge/internal/graphqlapi"
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/schemas"
+	"BigDataForge/internal/services"
+	"BigDataForge/internal/validators"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/graphql-go/graphql"
+)
+
+type GraphQLController struct {
+	Schema graphql.Schema
+	Limits graphqlapi.Limits
+}
+
+func NewGraphQLController(redisClient *redis.Client, esFactory *elastic.Factory, schemaRegistry *schemas.Registry, limits graphqlapi.Limits) *GraphQLController {
+	schema, err := graphqlapi.NewSchema(services.NewPlanService(redisClient, esFactory), validators.NewPlanValidator(schemaRegistry))
+	if err != nil {
+		log.Fatalf("Failed to build GraphQL schema: %v", err)
+	}
+	return &GraphQLController{Schema: schema, Limits: limits}
+}
+
+// Query executes a POSTed GraphQL request. GraphQL errors are reported in the
+// response body with status 200; only unreadable requests get a problem.
+func (controller *GraphQLController) Query(c *gin.Context) {
+	var req graphqlapi.Request
+	if err := c.ShouldBindJSON(&req); err != nil || req.Query == "" {
+		problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Expected a JSON body with a query")
+		return
+	}
+
+	c.JSON(http.StatusOK, graphqlapi.Execute(services.RequestContext(c), controller.Schema, controller.Limits, req))
+}
@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"BigDataForge/internal/health"
+	"BigDataForge/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController struct {
+	Service *services.HealthService
+}
+
+func NewHealthController(checker *health.Checker) *HealthController {
+	return &HealthController{
+		Service: services.NewHealthService(checker),
+	}
+}
+
+func (controller *HealthController) Live(c *gin.Context) {
+	controller.Service.Live(c)
+}
+
+func (controller *HealthController) Ready(c *gin.Context) {
+	controller.Service.Ready(c)
+}
+
+func (controller *HealthController) Status(c *gin.Context) {
+	controller.Service.Status(c)
+}
@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"BigDataForge/internal/services"
+	"BigDataForge/internal/tenancy"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MembershipController struct {
+	Service *services.MembershipService
+}
+
+func NewMembershipController(store *tenancy.Store) *MembershipController {
+	return &MembershipController{
+		Service: services.NewMembershipService(store),
+	}
+}
+
+func (controller *MembershipController) GetMemberships(c *gin.Context) {
+	controller.Service.GetMemberships(c)
+}
+
+func (controller *MembershipController) GrantMembership(c *gin.Context) {
+	controller.Service.GrantMembership(c)
+}
+
+func (controller *MembershipController) RevokeMembership(c *gin.Context) {
+	controller.Service.RevokeMembership(c)
+}
@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"BigDataForge/internal/resources"
+	"BigDataForge/internal/schemas"
+	"BigDataForge/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OpenAPIController struct {
+	Service *services.OpenAPIService
+}
+
+func NewOpenAPIController(router *gin.Engine, schemaRegistry *schemas.Registry, registry *resources.Registry) *OpenAPIController {
+	return &OpenAPIController{
+		Service: services.NewOpenAPIService(router, schemaRegistry, registry),
+	}
+}
+
+func (controller *OpenAPIController) GetDocument(c *gin.Context) {
+	controller.Service.GetDocument(c)
+}
+
+func (controller *OpenAPIController) GetDocs(c *gin.Context) {
+	controller.Service.GetDocs(c)
+}
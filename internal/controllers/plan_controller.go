@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"BigDataForge/internal/elastic"
+	"BigDataForge/internal/schemas"
 	"BigDataForge/internal/services"
 	"BigDataForge/internal/validators"
 
@@ -11,17 +12,21 @@ import (
 
 type PlanController struct {
 	Service *services.PlanService
+	Schemas *schemas.Registry
+	Rules   *validators.RuleEngine
 }
 
-func NewPlanController(redisClient *redis.Client, esFactory *elastic.Factory) *PlanController {
+func NewPlanController(redisClient *redis.Client, esFactory *elastic.Factory, schemaRegistry *schemas.Registry) *PlanController {
 	return &PlanController{
 		Service: services.NewPlanService(redisClient, esFactory),
+		Schemas: schemaRegistry,
+		Rules:   validators.NewRuleEngine(validators.DefaultPlanRules()...),
 	}
 }
 
 func (controller *PlanController) CreatePlan(c *gin.Context) {
 
-	if !validators.ValidatePlanSchema(c) {
+	if !validators.ValidatePlanSchema(c, controller.Schemas) || !validators.ValidatePlanRules(c, controller.Rules) {
 		return
 	}
 
@@ -37,14 +42,14 @@ func (controller *PlanController) DeletePlan(c *gin.Context) {
 }
 
 func (controller *PlanController) PatchPlan(c *gin.Context) {
-	if !validators.ValidatePlanSchema(c) {
+	if !validators.ValidatePlanSchema(c, controller.Schemas) || !validators.ValidatePlanRules(c, controller.Rules) {
 		return
 	}
 	controller.Service.PatchPlan(c)
 }
 
 func (controller *PlanController) UpdatePlan(c *gin.Context) {
-	if !validators.ValidatePlanSchema(c) {
+	if !validators.ValidatePlanSchema(c, controller.Schemas) || !validators.ValidatePlanRules(c, controller.Rules) {
 		return
 	}
 	controller.Service.UpdatePlan(c)
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"BigDataForge/internal/elastic"
+	"BigDataForge/internal/resources"
+	"BigDataForge/internal/services"
+	"BigDataForge/internal/validators"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+type ResourceController struct {
+	Resource *resources.Resource
+	Service  *services.ResourceService
+}
+
+func NewResourceController(redisClient *redis.Client, esFactory *elastic.Factory, resource *resources.Resource) *ResourceController {
+	return &ResourceController{
+		Resource: resource,
+		Service:  services.NewResourceService(redisClient, esFactory, resource),
+	}
+}
+
+func (controller *ResourceController) CreateResource(c *gin.Context) {
+	if !validators.ValidateResourceSchema(c, controller.Resource) {
+		return
+	}
+	controller.Service.CreateResource(c)
+}
+
+func (controller *ResourceController) GetResource(c *gin.Context) {
+	controller.Service.GetResource(c)
+}
+
+func (controller *ResourceController) DeleteResource(c *gin.Context) {
+	controller.Service.DeleteResource(c)
+}
+
+func (controller *ResourceController) PatchResource(c *gin.Context) {
+	if !validators.ValidateResourceSchema(c, controller.Resource) {
+		return
+	}
+	controller.Service.PatchResource(c)
+}
+
+func (controller *ResourceController) UpdateResource(c *gin.Context) {
+	if !validators.ValidateResourceSchema(c, controller.Resource) {
+		return
+	}
+	controller.Service.UpdateResource(c)
+}
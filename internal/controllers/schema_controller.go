@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"BigDataForge/internal/schemas"
+	"BigDataForge/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SchemaController struct {
+	Service *services.SchemaService
+}
+
+func NewSchemaController(registry *schemas.Registry) *SchemaController {
+	return &SchemaController{
+		Service: services.NewSchemaService(registry),
+	}
+}
+
+func (controller *SchemaController) ListSchemas(c *gin.Context) {
+	controller.Service.ListSchemas(c)
+}
+
+func (controller *SchemaController) ListSchemaVersions(c *gin.Context) {
+	controller.Service.ListSchemaVersions(c)
+}
+
+func (controller *SchemaController) GetSchemaVersion(c *gin.Context) {
+	controller.Service.GetSchemaVersion(c)
+}
+
+func (controller *SchemaController) UploadSchema(c *gin.Context) {
+	controller.Service.UploadSchema(c)
+}
+
+func (controller *SchemaController) ActivateSchema(c *gin.Context) {
+	controller.Service.ActivateSchema(c)
+}
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"BigDataForge/internal/services"
+	"BigDataForge/internal/webhooks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+type WebhookController struct {
+	Service *services.WebhookService
+}
+
+func NewWebhookController(redisClient *redis.Client) *WebhookController {
+	return &WebhookController{
+		Service: services.NewWebhookService(webhooks.NewStore(redisClient)),
+	}
+}
+
+func (controller *WebhookController) CreateWebhook(c *gin.Context) {
+	controller.Service.CreateWebhook(c)
+}
+
+func (controller *WebhookController) ListWebhooks(c *gin.Context) {
+	controller.Service.ListWebhooks(c)
+}
+
+func (controller *WebhookController) GetWebhook(c *gin.Context) {
+	controller.Service.GetWebhook(c)
+}
+
+func (controller *WebhookController) DeleteWebhook(c *gin.Context) {
+	controller.Service.DeleteWebhook(c)
+}
+
+func (controller *WebhookController) EnableWebhook(c *gin.Context) {
+	controller.Service.EnableWebhook(c)
+}
+
+func (controller *WebhookController) ListWebhookDeliveries(c *gin.Context) {
+	controller.Service.ListWebhookDeliveries(c)
+}
@@ -0,0 +1,133 @@
+package elastic
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrUnavailable is returned instead of sending a request while the circuit
+// breaker is open.
+var ErrUnavailable = errors.New("elasticsearch is unavailable")
+
+// Breaker states
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+// Breaker stops requests to an unhealthy cluster, so callers fail fast rather
+// than wait for timeouts. It opens after threshold consecutive failures and
+// fails requests for the cooldown; then it lets one trial request through,
+// which closes it on success and opens it again on failure. A nil breaker, or
+// one with a threshold of 0, never opens.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{threshold: threshold, cooldown: cooldown}
+}
+
+// Allow reports whether a request may be sent. Each allowed request must be
+// followed by a call to Success, Failure or Skip.
+func (b *Breaker) Allow() bool {
+	if b == nil || b.threshold == 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.trial || time.Since(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+// Success records a request the cluster answered.
+func (b *Breaker) Success() {
+	if b == nil || b.threshold == 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures >= b.threshold {
+		slog.Info("Elasticsearch circuit breaker closed")
+	}
+	b.failures = 0
+	b.trial = false
+}
+
+// Failure records a request that failed because of the cluster.
+func (b *Breaker) Failure() {
+	if b == nil || b.threshold == 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.trial = false
+	if b.failures >= b.threshold {
+		if b.failures == b.threshold {
+			slog.Warn("Elasticsearch circuit breaker opened", "failures", b.failures, "cooldown", b.cooldown.String())
+		}
+		b.openedAt = time.Now()
+	}
+}
+
+// Skip records a request that says nothing about the cluster, such as one
+// canceled by its caller.
+func (b *Breaker) Skip() {
+	if b == nil || b.threshold == 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
+
+// State returns BreakerClosed, BreakerOpen or BreakerHalfOpen, once the
+// cooldown passed.
+func (b *Breaker) State() string {
+	if b == nil || b.threshold == 0 {
+		return BreakerClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case b.failures < b.threshold:
+		return BreakerClosed
+	case time.Since(b.openedAt) < b.cooldown:
+		return BreakerOpen
+	default:
+		return BreakerHalfOpen
+	}
+}
+
+// RetryAfter returns how long requests keep failing with ErrUnavailable: the
+// rest of the cooldown while the breaker is open, and 0 otherwise.
+func (b *Breaker) RetryAfter() time.Duration {
+	if b == nil || b.threshold == 0 {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return 0
+	}
+	if remaining := b.cooldown - time.Since(b.openedAt); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
@@ -1,28 +1,159 @@
 package elastic
 
 import (
-	"log"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
 	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"BigDataForge/internal/config"
 
 	"github.com/elastic/go-elasticsearch/v8"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
-type Factory struct{}
+// Factory holds the client of the configured cluster. PlanIndex holds the plan
+// documents indexed by the listener. The client is created on first use and
+// shared by all callers; Close releases its connections. Its requests are
+// traced as children of the request context.
+type Factory struct {
+	PlanIndex string
+	// Timeout bounds a search, retries included
+	Timeout time.Duration
+	// Breaker guards the client's requests
+	Breaker *Breaker
+
+	cfg       config.Elasticsearch
+	once      sync.Once
+	client    *Client
+	err       error
+	transport *http.Transport
+}
 
-func (f *Factory) NewClient() (*elasticsearch.Client, error) {
-	elasticURL := os.Getenv("ELASTICSEARCH_URL")
-	if elasticURL == "" {
-		elasticURL = "http://localhost:9200"
+func NewFactory(cfg config.Elasticsearch) *Factory {
+	return &Factory{
+		PlanIndex: cfg.Index,
+		Timeout:   cfg.Timeout.Duration(),
+		Breaker:   NewBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown.Duration()),
+		cfg:       cfg,
 	}
-	cfg := elasticsearch.Config{
-		Addresses: []string{
-			elasticURL,
-		},
+}
+
+// Client returns the shared client, creating it on first use. Requests to
+// nodes answering 429, 502, 503 or 504, or failing to connect, are retried on
+// the next node with an exponential backoff.
+func (f *Factory) Client() (*Client, error) {
+	f.once.Do(func() {
+		f.client, f.err = f.newClient()
+	})
+	return f.client, f.err
+}
+
+// Helper to create the client of the configured cluster
+func (f *Factory) newClient() (*Client, error) {
+	f.transport = http.DefaultTransport.(*http.Transport).Clone()
+	if f.cfg.CACert != "" {
+		pem, err := os.ReadFile(f.cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Elasticsearch CA certificate: %w", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", f.cfg.CACert)
+		}
+		f.transport.TLSClientConfig = &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
 	}
-	client, err := elasticsearch.NewClient(cfg)
+
+	client, err := elasticsearch.NewClient(elasticsearch.Config{
+		Addresses:     f.cfg.URLs,
+		Username:      f.cfg.Username,
+		Password:      f.cfg.Password,
+		APIKey:        f.cfg.APIKey,
+		RetryOnStatus: []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+		DisableRetry:  f.cfg.MaxRetries == 0,
+		MaxRetries:    f.cfg.MaxRetries,
+		RetryOnError:  retryOnError,
+		RetryBackoff:  retryBackoff,
+		Transport:     otelhttp.NewTransport(f.transport, otelhttp.WithSpanNameFormatter(spanName)),
+	})
 	if err != nil {
-		log.Printf("Failed to create Elasticsearch client: %s", err)
-		return nil, err
+		return nil, fmt.Errorf("failed to create Elasticsearch client: %w", err)
+	}
+	return &Client{Client: client, breaker: f.Breaker}, nil
+}
+
+// Helper to retry failed connections, unless the caller gave up on the request
+func retryOnError(req *http.Request, _ error) bool {
+	return req.Context().Err() == nil
+}
+
+// Helper to wait 100ms before the first retry, doubling up to 2s
+func retryBackoff(attempt int) time.Duration {
+	backoff := 100 * time.Millisecond << (attempt - 1)
+	if backoff <= 0 || backoff > 2*time.Second {
+		return 2 * time.Second
+	}
+	return backoff
+}
+
+// Helper to name request spans after the API they call, such as
+// "elasticsearch PUT _doc", rather than after document IDs
+func spanName(_ string, r *http.Request) string {
+	name := "elasticsearch " + r.Method
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if strings.HasPrefix(segments[i], "_") {
+			return name + " " + segments[i]
+		}
+	}
+	return name
+}
+
+// Close closes the idle connections of the client. Requests in flight finish
+// first, so call it after the callers stopped.
+func (f *Factory) Close() error {
+	if f.transport != nil {
+		f.transport.CloseIdleConnections()
+	}
+	return nil
+}
+
+// Client is the shared client of the cluster. Requests sent with esapi
+// requests' Do go through the factory's circuit breaker: while it is open they
+// fail with ErrUnavailable without being sent. The typed APIs of the embedded
+// client, such as Cluster.Health, bypass it, so health checks still reach an
+// unhealthy cluster.
+type Client struct {
+	*elasticsearch.Client
+	breaker *Breaker
+}
+
+// RetryAfter returns how long requests keep failing with ErrUnavailable.
+func (c *Client) RetryAfter() time.Duration {
+	return c.breaker.RetryAfter()
+}
+
+// Perform sends a request unless the breaker is open. Connection errors,
+// timeouts and 429 or 5xx responses left after retries count as failures;
+// requests canceled by their caller are not counted.
+func (c *Client) Perform(req *http.Request) (*http.Response, error) {
+	if !c.breaker.Allow() {
+		return nil, ErrUnavailable
+	}
+	res, err := c.Client.Perform(req)
+	switch {
+	case err != nil && errors.Is(req.Context().Err(), context.Canceled):
+		c.breaker.Skip()
+	case err != nil, res.StatusCode == http.StatusTooManyRequests, res.StatusCode >= http.StatusInternalServerError:
+		c.breaker.Failure()
+	default:
+		c.breaker.Success()
 	}
-	return client, nil
+	return res, err
 }
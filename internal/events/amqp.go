@@ -0,0 +1,87 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"BigDataForge/internal/changefeed"
+
+	"github.com/streadway/amqp"
+)
+
+// Publishing turns a plan change event into a message whose body is its
+// envelope. The message properties repeat the ID, type and time for brokers
+// and tools, and carry the ID of the API request as correlation ID.
+func Publishing(event changefeed.Event) (amqp.Publishing, error) {
+	envelope := FromChange(event)
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return amqp.Publishing{}, err
+	}
+	return amqp.Publishing{
+		ContentType:   ContentType,
+		DeliveryMode:  amqp.Persistent,
+		MessageId:     envelope.ID,
+		CorrelationId: event.RequestID,
+		Type:          envelope.Type,
+		Timestamp:     envelope.Time,
+		Headers:       amqp.Table{},
+		Body:          body,
+	}, nil
+}
+
+// FromDelivery reads the plan change event of a message, upcasting its data.
+// Messages published before the envelope are still read: their metadata is in
+// the message properties and their body is the plan, or, without a type, a
+// bare plan that is treated as a create.
+func FromDelivery(d amqp.Delivery) (changefeed.Event, error) {
+	if d.ContentType != ContentType {
+		return legacyEvent(d)
+	}
+	var envelope Envelope
+	if err := json.Unmarshal(d.Body, &envelope); err != nil {
+		return changefeed.Event{}, fmt.Errorf("invalid event envelope: %w", err)
+	}
+	event, err := envelope.Change()
+	if err != nil {
+		return changefeed.Event{}, err
+	}
+	event.RequestID = d.CorrelationId
+	return event, nil
+}
+
+// Helper to read a message published before the envelope
+func legacyEvent(d amqp.Delivery) (changefeed.Event, error) {
+	header := func(name string) string {
+		value, _ := d.Headers[name].(string)
+		return value
+	}
+	event := changefeed.Event{
+		ID:        d.MessageId,
+		Type:      d.Type,
+		PlanID:    header("planId"),
+		Org:       header("org"),
+		ETag:      header("etag"),
+		Timestamp: d.Timestamp,
+		Document:  d.Body,
+		RequestID: d.CorrelationId,
+	}
+	if event.Type == "" {
+		event.Type = "create"
+	}
+	if event.PlanID == "" && len(event.Document) > 0 {
+		var plan struct {
+			ObjectID string `json:"objectId"`
+			Org      string `json:"_org"`
+		}
+		if err := json.Unmarshal(event.Document, &plan); err != nil {
+			return changefeed.Event{}, fmt.Errorf("failed to deserialize Plan: %w", err)
+		}
+		event.PlanID, event.Org = plan.ObjectID, plan.Org
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	return event, nil
+}
@@ -0,0 +1,109 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"BigDataForge/internal/changefeed"
+)
+
+// Plan change events travel between the binaries as CloudEvents 1.0 in
+// structured JSON mode: the message body is the envelope, and the plan
+// document its data. The dataschema names the version of the data, so that
+// consumers upcast messages published before a model change (see Upcast).
+
+const (
+	SpecVersion = "1.0"
+	// ContentType marks messages whose body is an envelope
+	ContentType = "application/cloudevents+json"
+	// Source is the context plan changes happen in
+	Source = "/api/v1/plans"
+	// TypePrefix precedes the change type, as in "com.bigdataforge.plan.update"
+	TypePrefix = "com.bigdataforge.plan."
+	// PlanVersion is the version of the data of plan events published now
+	PlanVersion = 1
+
+	dataSchemaPrefix = "urn:bigdataforge:event:plan:v"
+)
+
+// Envelope is a CloudEvents event. Org and ETag are extension attributes; the
+// ID of the API request and the trace travel in the message properties.
+type Envelope struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	DataSchema      string          `json:"dataschema,omitempty"`
+	Org             string          `json:"org,omitempty"`
+	ETag            string          `json:"etag,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+}
+
+// DataSchema returns the dataschema of a version of plan event data.
+func DataSchema(version int) string {
+	return dataSchemaPrefix + strconv.Itoa(version)
+}
+
+// FromChange wraps a plan change event in an envelope. The subject is the
+// plan ID; deletes have no data.
+func FromChange(event changefeed.Event) Envelope {
+	envelope := Envelope{
+		SpecVersion: SpecVersion,
+		ID:          event.ID,
+		Source:      Source,
+		Type:        TypePrefix + event.Type,
+		Subject:     event.PlanID,
+		Time:        event.Timestamp,
+		DataSchema:  DataSchema(PlanVersion),
+		Org:         event.Org,
+		ETag:        event.ETag,
+	}
+	if len(event.Document) > 0 {
+		envelope.DataContentType = "application/json"
+		envelope.Data = event.Document
+	}
+	return envelope
+}
+
+// Version returns the version of the envelope's data.
+func (e Envelope) Version() (int, error) {
+	version, err := strconv.Atoi(strings.TrimPrefix(e.DataSchema, dataSchemaPrefix))
+	if !strings.HasPrefix(e.DataSchema, dataSchemaPrefix) || err != nil || version < 1 {
+		return 0, fmt.Errorf("unknown dataschema %q", e.DataSchema)
+	}
+	return version, nil
+}
+
+// Change returns the plan change event of an envelope, with its data upcast
+// to PlanVersion.
+func (e Envelope) Change() (changefeed.Event, error) {
+	if e.SpecVersion != SpecVersion {
+		return changefeed.Event{}, fmt.Errorf("unsupported specversion %q", e.SpecVersion)
+	}
+	if !strings.HasPrefix(e.Type, TypePrefix) {
+		return changefeed.Event{}, fmt.Errorf("unknown event type %q", e.Type)
+	}
+	if e.ID == "" || e.Subject == "" {
+		return changefeed.Event{}, errors.New("event has no id or subject")
+	}
+	upcast, err := Upcast(e)
+	if err != nil {
+		return changefeed.Event{}, err
+	}
+	return changefeed.Event{
+		ID:        upcast.ID,
+		Type:      strings.TrimPrefix(upcast.Type, TypePrefix),
+		PlanID:    upcast.Subject,
+		Org:       upcast.Org,
+		ETag:      upcast.ETag,
+		Timestamp: upcast.Time,
+		Document:  upcast.Data,
+	}, nil
+}
@@ -0,0 +1,42 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Upcaster converts plan event data from one version to the next.
+type Upcaster func(data json.RawMessage) (json.RawMessage, error)
+
+// upcasters are keyed by the version they convert from. When the data of plan
+// events changes, bump PlanVersion and add the upcaster from the previous
+// version here, so that messages published before the change, and still
+// waiting in queues, are processed as current ones.
+var upcasters = map[int]Upcaster{}
+
+// Upcast returns the envelope with its data converted to PlanVersion, one
+// version at a time. Data newer than PlanVersion, from a binary deployed
+// ahead of this one, is an error.
+func Upcast(e Envelope) (Envelope, error) {
+	version, err := e.Version()
+	if err != nil {
+		return e, err
+	}
+	if version > PlanVersion {
+		return e, fmt.Errorf("event data version %d is newer than %d", version, PlanVersion)
+	}
+	for ; version < PlanVersion; version++ {
+		upcaster, ok := upcasters[version]
+		if !ok {
+			return e, fmt.Errorf("no upcaster from event data version %d", version)
+		}
+		// Deletes have no data to convert
+		if len(e.Data) > 0 {
+			if e.Data, err = upcaster(e.Data); err != nil {
+				return e, fmt.Errorf("failed to upcast event data from version %d: %w", version, err)
+			}
+		}
+	}
+	e.DataSchema = DataSchema(PlanVersion)
+	return e, nil
+}
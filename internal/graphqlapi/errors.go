@@ -0,0 +1,47 @@
+package graphqlapi
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"BigDataForge/internal/problems"
+)
+
+// Error extension codes of requests rejected before execution
+const (
+	CodeQueryTooDeep    = "query_too_deep"
+	CodeQueryTooComplex = "query_too_complex"
+)
+
+// problemError exposes a service problem as a GraphQL error whose extensions
+// carry the same code, status and field errors as REST problem documents.
+type problemError struct {
+	problem *problems.Error
+}
+
+func (e problemError) Error() string {
+	return e.problem.Detail
+}
+
+func (e problemError) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{
+		"code":   e.problem.Code,
+		"status": e.problem.Status,
+	}
+	if len(e.problem.Errors) > 0 {
+		extensions["errors"] = e.problem.Errors
+	}
+	return extensions
+}
+
+// resolverError converts a service error into a GraphQL error. Errors other
+// than problems are logged and reported as internal errors.
+func resolverError(err error) error {
+	var problemErr *problems.Error
+	if !errors.As(err, &problemErr) {
+		slog.Error("GraphQL resolver failed", "error", err)
+		problemErr = problems.NewError(http.StatusInternalServerError, problems.CodeInternal, "Internal server error")
+	}
+	return problemError{problem: problemErr}
+}
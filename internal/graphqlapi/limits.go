@@ -0,0 +1,135 @@
+package graphqlapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Queries are measured before they run. Depth counts nested field
+// selections; complexity counts selected fields, multiplying the fields
+// selected below a list by listFactor. Introspection fields are not counted.
+
+// listFactor is the list size assumed when estimating complexity.
+const listFactor = 10
+
+// Limits bound the depth and complexity of a query.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// check measures the selected operation of a validated document.
+func (l Limits) check(schema *graphql.Schema, document *ast.Document, operationName string) *gqlerrors.FormattedError {
+	measure := &measure{fragments: map[string]*ast.FragmentDefinition{}}
+	var operation *ast.OperationDefinition
+	for _, definition := range document.Definitions {
+		switch definition := definition.(type) {
+		case *ast.FragmentDefinition:
+			measure.fragments[definition.Name.Value] = definition
+		case *ast.OperationDefinition:
+			if operationName == "" || (definition.Name != nil && definition.Name.Value == operationName) {
+				operation = definition
+			}
+		}
+	}
+	if operation == nil {
+		// Execution reports the missing operation
+		return nil
+	}
+
+	root := schema.QueryType()
+	if operation.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+	depth, complexity := measure.selectionSet(operation.SelectionSet, root, map[string]bool{})
+
+	if depth > l.MaxDepth {
+		return limitError(CodeQueryTooDeep, fmt.Sprintf("Query depth %d exceeds the limit of %d", depth, l.MaxDepth))
+	}
+	if complexity > l.MaxComplexity {
+		return limitError(CodeQueryTooComplex, fmt.Sprintf("Query complexity %d exceeds the limit of %d", complexity, l.MaxComplexity))
+	}
+	return nil
+}
+
+func limitError(code, message string) *gqlerrors.FormattedError {
+	return &gqlerrors.FormattedError{Message: message, Extensions: map[string]interface{}{"code": code}}
+}
+
+type measure struct {
+	fragments map[string]*ast.FragmentDefinition
+}
+
+// Helper to measure a selection set of parent. visiting guards against fragment cycles.
+func (m *measure) selectionSet(set *ast.SelectionSet, parent *graphql.Object, visiting map[string]bool) (depth, complexity int) {
+	if set == nil {
+		return 0, 0
+	}
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			name := selection.Name.Value
+			if strings.HasPrefix(name, "__") {
+				continue
+			}
+			fieldDepth, fieldComplexity := 1, 1
+			if child, isList := fieldType(parent, name); child != nil {
+				childDepth, childComplexity := m.selectionSet(selection.SelectionSet, child, visiting)
+				if isList {
+					childComplexity *= listFactor
+				}
+				fieldDepth += childDepth
+				fieldComplexity += childComplexity
+			}
+			depth = max(depth, fieldDepth)
+			complexity += fieldComplexity
+		case *ast.InlineFragment:
+			fragmentDepth, fragmentComplexity := m.selectionSet(selection.SelectionSet, parent, visiting)
+			depth = max(depth, fragmentDepth)
+			complexity += fragmentComplexity
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			fragment, ok := m.fragments[name]
+			if !ok || visiting[name] {
+				continue
+			}
+			visiting[name] = true
+			fragmentDepth, fragmentComplexity := m.selectionSet(fragment.SelectionSet, parent, visiting)
+			delete(visiting, name)
+			depth = max(depth, fragmentDepth)
+			complexity += fragmentComplexity
+		}
+	}
+	return depth, complexity
+}
+
+// Helper to find the object type a field of parent returns, and whether it is a list
+func fieldType(parent *graphql.Object, name string) (*graphql.Object, bool) {
+	if parent == nil {
+		return nil, false
+	}
+	field, ok := parent.Fields()[name]
+	if !ok {
+		return nil, false
+	}
+	isList := false
+	fieldType := field.Type
+	for {
+		switch wrapper := fieldType.(type) {
+		case *graphql.NonNull:
+			fieldType = wrapper.OfType
+			continue
+		case *graphql.List:
+			isList = true
+			fieldType = wrapper.OfType
+			continue
+		}
+		break
+	}
+	object, _ := fieldType.(*graphql.Object)
+	return object, isList
+}
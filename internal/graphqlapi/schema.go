@@ -0,0 +1,269 @@
+package graphqlapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"BigDataForge/internal/models"
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/schemas"
+	"BigDataForge/internal/services"
+	"BigDataForge/internal/validators"
+
+	"github.com/graphql-go/graphql"
+)
+
+// maxPlanIDs bounds the plans(ids:) query.
+const maxPlanIDs = 100
+
+// resolvers map GraphQL fields onto the plan service, so they are authorized,
+// validated and audited exactly like the REST routes.
+type resolvers struct {
+	plans     *services.PlanService
+	validator *validators.PlanValidator
+}
+
+// PlanWrite is the result of a plan mutation.
+type PlanWrite struct {
+	PlanID string `json:"planId"`
+	ETag   string `json:"etag"`
+}
+
+// searchHit wraps a service search hit for the SearchHit type.
+type searchHit struct {
+	services.SearchHit
+}
+
+// Helper to read the join relation of an indexed document ("plan", "linkedPlanServices", ...)
+func (hit searchHit) relation() string {
+	if join, ok := hit.Source["plan_join"].(map[string]interface{}); ok {
+		name, _ := join["name"].(string)
+		return name
+	}
+	return ""
+}
+
+// NewSchema builds the GraphQL schema of the plan API.
+func NewSchema(plans *services.PlanService, validator *validators.PlanValidator) (graphql.Schema, error) {
+	r := &resolvers{plans: plans, validator: validator}
+	types := newTypeBuilder()
+	planType := types.object(reflect.TypeOf(models.Plan{}))
+	planInput := types.input(reflect.TypeOf(models.Plan{}))
+
+	searchHitType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "SearchHit",
+		Fields: graphql.Fields{
+			"id":    &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(searchHit).ID, nil }},
+			"score": &graphql.Field{Type: graphql.Float, Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(searchHit).Score, nil }},
+			"relation": &graphql.Field{
+				Type:        graphql.String,
+				Description: "Join relation of the indexed document: plan, planCostShares, linkedPlanServices, ...",
+				Resolve:     func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(searchHit).relation(), nil },
+			},
+			"source": &graphql.Field{Type: JSON, Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(searchHit).Source, nil }},
+			"plan": &graphql.Field{
+				Type:        planType,
+				Description: "The matched plan, when the hit is a plan document.",
+				Resolve:     r.searchHitPlan,
+			},
+		},
+	})
+	searchResultType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "SearchResult",
+		Fields: graphql.Fields{
+			"total": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"hits":  &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(searchHitType)))},
+		},
+	})
+	planWriteType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PlanWrite",
+		Fields: graphql.Fields{
+			"planId": &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"etag":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"plan": &graphql.Field{
+				Type:    planType,
+				Args:    graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: r.plan,
+			},
+			"plans": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(planType)),
+				Description: fmt.Sprintf("Plans by ID, null for plans that do not exist. At most %d IDs.", maxPlanIDs),
+				Args:        graphql.FieldConfigArgument{"ids": {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.ID)))}},
+				Resolve:     r.planList,
+			},
+			"searchPlans": &graphql.Field{
+				Type: graphql.NewNonNull(searchResultType),
+				Args: graphql.FieldConfigArgument{
+					"key":   {Type: graphql.NewNonNull(graphql.String)},
+					"value": {Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: r.searchPlans,
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createPlan": &graphql.Field{
+				Type:    graphql.NewNonNull(planWriteType),
+				Args:    graphql.FieldConfigArgument{"plan": {Type: graphql.NewNonNull(planInput)}},
+				Resolve: r.createPlan,
+			},
+			"updatePlan": &graphql.Field{
+				Type:    graphql.NewNonNull(planWriteType),
+				Args:    graphql.FieldConfigArgument{"plan": {Type: graphql.NewNonNull(planInput)}},
+				Resolve: r.updatePlan,
+			},
+			"patchPlan": &graphql.Field{
+				Type: graphql.NewNonNull(planWriteType),
+				Args: graphql.FieldConfigArgument{
+					"id":      {Type: graphql.NewNonNull(graphql.ID)},
+					"plan":    {Type: graphql.NewNonNull(planInput)},
+					"ifMatch": {Type: graphql.String},
+				},
+				Resolve: r.patchPlan,
+			},
+			"deletePlan": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.Boolean),
+				Args:    graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: r.deletePlan,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+// Helper to load a plan, treating plans that don't exist or can't be seen as null
+func (r *resolvers) getPlan(ctx context.Context, id string) (*models.Plan, error) {
+	stored, err := r.plans.Get(ctx, id)
+	var problemErr *problems.Error
+	if errors.As(err, &problemErr) && problemErr.Code == problems.CodeNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return stored.Plan, nil
+}
+
+func (r *resolvers) plan(p graphql.ResolveParams) (interface{}, error) {
+	plan, err := r.getPlan(p.Context, p.Args["id"].(string))
+	if plan == nil || err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (r *resolvers) planList(p graphql.ResolveParams) (interface{}, error) {
+	ids := p.Args["ids"].([]interface{})
+	if len(ids) > maxPlanIDs {
+		return nil, resolverError(problems.NewError(http.StatusBadRequest, problems.CodeInvalidRequest, fmt.Sprintf("At most %d plan IDs can be requested at once", maxPlanIDs)))
+	}
+	plans := make([]interface{}, len(ids))
+	for i, id := range ids {
+		plan, err := r.getPlan(p.Context, id.(string))
+		if err != nil {
+			return nil, err
+		}
+		if plan != nil {
+			plans[i] = plan
+		}
+	}
+	return plans, nil
+}
+
+func (r *resolvers) searchPlans(p graphql.ResolveParams) (interface{}, error) {
+	result, err := r.plans.Search(p.Context, models.SearchRequest{Key: p.Args["key"].(string), Value: p.Args["value"].(string)})
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	total, hits := services.SearchHits(result)
+	wrapped := make([]interface{}, 0, len(hits))
+	for _, hit := range hits {
+		wrapped = append(wrapped, searchHit{hit})
+	}
+	return map[string]interface{}{"total": total, "hits": wrapped}, nil
+}
+
+// Plan documents are indexed whole, so the plan is decoded from the hit itself
+func (r *resolvers) searchHitPlan(p graphql.ResolveParams) (interface{}, error) {
+	hit := p.Source.(searchHit)
+	if hit.relation() != "plan" {
+		return nil, nil
+	}
+	document, err := json.Marshal(hit.Source)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	var plan models.Plan
+	if err := json.Unmarshal(document, &plan); err != nil {
+		return nil, resolverError(err)
+	}
+	return &plan, nil
+}
+
+// Helper to validate a plan argument like a REST body
+func (r *resolvers) validatePlan(ctx context.Context, arg interface{}, schemaName string) (models.Plan, string, error) {
+	body, err := json.Marshal(arg)
+	if err != nil {
+		return models.Plan{}, "", problems.NewError(http.StatusBadRequest, problems.CodeInvalidRequest, "Invalid data")
+	}
+	return r.validator.Validate(ctx, schemaName, body)
+}
+
+func (r *resolvers) createPlan(p graphql.ResolveParams) (interface{}, error) {
+	plan, schemaVersion, err := r.validatePlan(p.Context, p.Args["plan"], schemas.PlanSchema)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	eTag, err := r.plans.Create(p.Context, plan, schemaVersion)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return PlanWrite{PlanID: plan.ObjectID, ETag: eTag}, nil
+}
+
+func (r *resolvers) updatePlan(p graphql.ResolveParams) (interface{}, error) {
+	plan, schemaVersion, err := r.validatePlan(p.Context, p.Args["plan"], schemas.PlanSchema)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	eTag, err := r.plans.Update(p.Context, plan, schemaVersion)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return PlanWrite{PlanID: plan.ObjectID, ETag: eTag}, nil
+}
+
+func (r *resolvers) patchPlan(p graphql.ResolveParams) (interface{}, error) {
+	id := p.Args["id"].(string)
+	ifMatch, _ := p.Args["ifMatch"].(string)
+	plan, schemaVersion, err := r.validatePlan(p.Context, p.Args["plan"], schemas.PatchPlanSchema)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	eTag, err := r.plans.Patch(p.Context, id, plan, ifMatch, schemaVersion)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return PlanWrite{PlanID: id, ETag: eTag}, nil
+}
+
+func (r *resolvers) deletePlan(p graphql.ResolveParams) (interface{}, error) {
+	if err := r.plans.Delete(p.Context, p.Args["id"].(string)); err != nil {
+		return nil, resolverError(err)
+	}
+	return true, nil
+}
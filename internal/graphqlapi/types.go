@@ -0,0 +1,131 @@
+package graphqlapi
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// GraphQL types are derived from the structs in internal/models. Every field
+// with a JSON name becomes a GraphQL field of the same name, so queries select
+// the names of the REST documents (including "_org"). Map fields, such as the
+// Elasticsearch join field, are left out.
+
+// JSON is the scalar of raw documents, such as search hits of child objects.
+var JSON = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "JSON",
+	Description: "An arbitrary JSON value.",
+	Serialize:   func(value interface{}) interface{} { return value },
+})
+
+type typeBuilder struct {
+	objects map[reflect.Type]*graphql.Object
+	inputs  map[reflect.Type]*graphql.InputObject
+}
+
+func newTypeBuilder() *typeBuilder {
+	return &typeBuilder{
+		objects: map[reflect.Type]*graphql.Object{},
+		inputs:  map[reflect.Type]*graphql.InputObject{},
+	}
+}
+
+// Helper to read the JSON name of a struct field, "" when it has none
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" || !field.IsExported() {
+		return ""
+	}
+	return name
+}
+
+// object derives the output type of a model struct, named after the struct.
+func (b *typeBuilder) object(t reflect.Type) *graphql.Object {
+	if object, ok := b.objects[t]; ok {
+		return object
+	}
+	fields := graphql.Fields{}
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonName(t.Field(i))
+		fieldType := b.output(t.Field(i).Type)
+		if name == "" || fieldType == nil {
+			continue
+		}
+		fields[name] = &graphql.Field{Type: fieldType}
+	}
+	object := graphql.NewObject(graphql.ObjectConfig{Name: t.Name(), Fields: fields})
+	b.objects[t] = object
+	return object
+}
+
+// Helper to map a Go type to an output type. Values are non-null; slices may be null.
+func (b *typeBuilder) output(t reflect.Type) graphql.Output {
+	switch t.Kind() {
+	case reflect.Slice:
+		if elem := b.output(t.Elem()); elem != nil {
+			return graphql.NewList(elem)
+		}
+		return nil
+	case reflect.Struct:
+		return graphql.NewNonNull(b.object(t))
+	}
+	if scalar := scalarOf(t); scalar != nil {
+		return graphql.NewNonNull(scalar)
+	}
+	return nil
+}
+
+// input derives the input type of a model struct, named after the struct with
+// an "Input" suffix. Input fields are nullable: the plan JSON schemas decide
+// which fields are required, as they do for REST bodies.
+func (b *typeBuilder) input(t reflect.Type) *graphql.InputObject {
+	if input, ok := b.inputs[t]; ok {
+		return input
+	}
+	fields := graphql.InputObjectConfigFieldMap{}
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonName(t.Field(i))
+		fieldType := b.inputField(t.Field(i).Type)
+		if name == "" || fieldType == nil {
+			continue
+		}
+		fields[name] = &graphql.InputObjectFieldConfig{Type: fieldType}
+	}
+	input := graphql.NewInputObject(graphql.InputObjectConfig{Name: t.Name() + "Input", Fields: fields})
+	b.inputs[t] = input
+	return input
+}
+
+// Helper to map a Go type to an input type
+func (b *typeBuilder) inputField(t reflect.Type) graphql.Input {
+	switch t.Kind() {
+	case reflect.Slice:
+		if elem := b.inputField(t.Elem()); elem != nil {
+			return graphql.NewList(elem)
+		}
+		return nil
+	case reflect.Struct:
+		return b.input(t)
+	}
+	if scalar := scalarOf(t); scalar != nil {
+		return scalar
+	}
+	return nil
+}
+
+// Helper to map a Go kind to a built-in scalar
+func scalarOf(t reflect.Type) *graphql.Scalar {
+	switch t.Kind() {
+	case reflect.String:
+		return graphql.String
+	case reflect.Bool:
+		return graphql.Boolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return graphql.Int
+	case reflect.Float32, reflect.Float64:
+		return graphql.Float
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package grpcapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"BigDataForge/internal/changefeed"
+	"BigDataForge/internal/grpcapi/planspb"
+	"BigDataForge/internal/models"
+	"BigDataForge/internal/problems"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Plans cross between protobuf and the service layer as JSON, using the
+// proto's json_name options, so gRPC requests are validated as the exact
+// documents a REST client would send.
+
+// Helper to render a plan message as a JSON document. Complete documents
+// include unset fields so the schema reports them; patches only carry the
+// fields that were set.
+func planDocument(plan *planspb.Plan, complete bool) ([]byte, error) {
+	body, err := protojson.MarshalOptions{EmitUnpopulated: complete}.Marshal(plan)
+	if err != nil {
+		return nil, problems.NewError(http.StatusBadRequest, problems.CodeInvalidRequest, "Invalid data")
+	}
+	return body, nil
+}
+
+// Helper to convert a JSON plan document into a plan message
+func planMessage(document []byte) (*planspb.Plan, error) {
+	var message planspb.Plan
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(document, &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// Helper to convert a stored plan into a plan message
+func planToProto(plan *models.Plan) (*planspb.Plan, error) {
+	document, err := json.Marshal(plan)
+	if err != nil {
+		return nil, err
+	}
+	return planMessage(document)
+}
+
+// Helper to convert a change feed event into a plan event message
+func eventToProto(event changefeed.Event) (*planspb.PlanEvent, error) {
+	message := &planspb.PlanEvent{
+		Id:        event.ID,
+		Type:      event.Type,
+		PlanId:    event.PlanID,
+		Org:       event.Org,
+		Etag:      event.ETag,
+		Timestamp: timestamppb.New(event.Timestamp),
+	}
+	if len(event.Document) > 0 {
+		document, err := planMessage(event.Document)
+		if err != nil {
+			return nil, err
+		}
+		message.Document = document
+	}
+	return message, nil
+}
@@ -0,0 +1,71 @@
+package grpcapi
+
+import (
+	"errors"
+	"log/slog"
+
+	"BigDataForge/internal/problems"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the domain of the ErrorInfo detail whose reason carries the
+// same stable code as REST problem documents.
+const ErrorDomain = "bigdataforge"
+
+var statusCodes = map[string]codes.Code{
+	problems.CodeInvalidRequest:     codes.InvalidArgument,
+	problems.CodeValidationFailed:   codes.InvalidArgument,
+	problems.CodeRuleViolation:      codes.InvalidArgument,
+	problems.CodeUnauthorized:       codes.Unauthenticated,
+	problems.CodeForbidden:          codes.PermissionDenied,
+	problems.CodeNotFound:           codes.NotFound,
+	problems.CodeConflict:           codes.AlreadyExists,
+	problems.CodePreconditionFailed: codes.FailedPrecondition,
+	problems.CodeRateLimited:        codes.ResourceExhausted,
+	problems.CodeEventsExpired:      codes.OutOfRange,
+	problems.CodeUnavailable:        codes.Unavailable,
+	problems.CodeInternal:           codes.Internal,
+}
+
+// statusError converts a service error into a gRPC status. Field errors
+// become BadRequest field violations keyed by JSON Pointer.
+func statusError(err error) error {
+	var problemErr *problems.Error
+	if !errors.As(err, &problemErr) {
+		slog.Error("gRPC call failed", "error", err)
+		return status.Error(codes.Internal, "Internal server error")
+	}
+	return problemStatus(problemErr.Code, problemErr.Detail, problemErr.Errors)
+}
+
+// Helper to build the status of a problem code
+func problemStatus(code, detail string, fieldErrors []problems.FieldError) error {
+	statusCode, ok := statusCodes[code]
+	if !ok {
+		statusCode = codes.Unknown
+	}
+	st := status.New(statusCode, detail)
+
+	errorInfo := &errdetails.ErrorInfo{Reason: code, Domain: ErrorDomain}
+	var withDetails *status.Status
+	var err error
+	if len(fieldErrors) == 0 {
+		withDetails, err = st.WithDetails(errorInfo)
+	} else {
+		badRequest := &errdetails.BadRequest{}
+		for _, fieldError := range fieldErrors {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       fieldError.Pointer,
+				Description: fieldError.Message,
+			})
+		}
+		withDetails, err = st.WithDetails(errorInfo, badRequest)
+	}
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
@@ -0,0 +1,175 @@
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+
+	"BigDataForge/internal/audit"
+	"BigDataForge/internal/auth"
+	"BigDataForge/internal/grpcapi/planspb"
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/ratelimit"
+	"BigDataForge/internal/requestid"
+	"BigDataForge/internal/tenancy"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
+)
+
+// RPCs limited by the write and search policies in addition to the default one
+var (
+	writeMethods = map[string]bool{
+		planspb.PlanService_CreatePlan_FullMethodName: true,
+		planspb.PlanService_UpdatePlan_FullMethodName: true,
+		planspb.PlanService_PatchPlan_FullMethodName:  true,
+		planspb.PlanService_DeletePlan_FullMethodName: true,
+	}
+	searchMethods = map[string]bool{
+		planspb.PlanService_SearchPlans_FullMethodName: true,
+	}
+)
+
+// guard does for RPCs what the /api/v1 middlewares do for HTTP requests:
+// assign a request ID, authenticate, resolve memberships and rate limit.
+type guard struct {
+	authenticator auth.Authenticator
+	resolver      *tenancy.Resolver
+	limiter       *ratelimit.Limiter
+	rateLimits    ratelimit.Policies
+}
+
+func (g *guard) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	// Signed requests sign the serialized request message as their body
+	var body []byte
+	if message, ok := req.(proto.Message); ok {
+		body, _ = proto.MarshalOptions{Deterministic: true}.Marshal(message)
+	}
+	ctx, err := g.admit(ctx, info.FullMethod, body)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (g *guard) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := g.admit(ss.Context(), info.FullMethod, nil)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &guardedStream{ServerStream: ss, ctx: ctx})
+}
+
+// guardedStream replaces the context of a stream with the admitted one.
+type guardedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *guardedStream) Context() context.Context {
+	return s.ctx
+}
+
+// Helper to admit a call, returning a context carrying the principal, tenant and origin
+func (g *guard) admit(ctx context.Context, fullMethod string, body []byte) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	id := firstValue(md, strings.ToLower(requestid.Header))
+	if id == "" || len(id) > 128 {
+		id = requestid.New()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(requestid.Header), id))
+
+	clientIP := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		clientIP, _, _ = net.SplitHostPort(p.Addr.String())
+	}
+
+	// Calls are limited per client IP before authentication, so invalid credentials cannot be tried endlessly
+	if err := g.allow(ctx, g.rateLimits.IP, ratelimit.CallerKey(nil, clientIP)); err != nil {
+		return nil, err
+	}
+
+	principal, err := g.authenticator.Authenticate(credentialRequest(ctx, md, fullMethod, body, clientIP))
+	if errors.Is(err, auth.ErrNoCredentials) {
+		return nil, problemStatus(problems.CodeUnauthorized, "No supported credentials provided", nil)
+	}
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		return nil, problemStatus(problems.CodeUnauthorized, err.Error(), nil)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Authentication failed", "error", err)
+		return nil, problemStatus(problems.CodeInternal, "Failed to authenticate request", nil)
+	}
+
+	tenant, err := g.resolver.Resolve(ctx, principal)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to resolve memberships", "subject", principal.Subject, "error", err)
+		return nil, problemStatus(problems.CodeInternal, "Failed to resolve organization memberships", nil)
+	}
+
+	policies := []ratelimit.Policy{g.rateLimits.Default}
+	if writeMethods[fullMethod] {
+		policies = append(policies, g.rateLimits.Write)
+	}
+	if searchMethods[fullMethod] {
+		policies = append(policies, g.rateLimits.Search)
+	}
+	key := ratelimit.CallerKey(principal, clientIP)
+	for _, policy := range policies {
+		if err := g.allow(ctx, policy, key); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx = auth.NewContext(ctx, principal)
+	ctx = tenancy.NewContext(ctx, tenant)
+	return audit.NewContext(ctx, audit.Origin{RequestID: id, ClientIP: clientIP}), nil
+}
+
+// Helper to take a token for the caller, failing open like the HTTP middleware
+func (g *guard) allow(ctx context.Context, policy ratelimit.Policy, key string) error {
+	if policy.Disabled() {
+		return nil
+	}
+	result, err := g.limiter.Allow(ctx, policy, key)
+	if err != nil {
+		slog.WarnContext(ctx, "Rate limiter unavailable", "error", err)
+		return nil
+	}
+	if !result.Allowed {
+		grpc.SetTrailer(ctx, metadata.Pairs("retry-after", result.Headers(policy)["Retry-After"]))
+		return problemStatus(problems.CodeRateLimited, fmt.Sprintf("Rate limit of %d requests per %s exceeded", policy.Limit, policy.Period), nil)
+	}
+	return nil
+}
+
+// Helper to present the call's metadata to the HTTP authenticators: metadata
+// become headers, and signed requests sign POST with the full method as URI.
+func credentialRequest(ctx context.Context, md metadata.MD, fullMethod string, body []byte, clientIP string) *http.Request {
+	r, _ := http.NewRequestWithContext(ctx, http.MethodPost, fullMethod, bytes.NewReader(body))
+	for key, values := range md {
+		if strings.HasPrefix(key, ":") {
+			continue
+		}
+		for _, value := range values {
+			r.Header.Add(key, value)
+		}
+	}
+	r.RemoteAddr = net.JoinHostPort(clientIP, "0")
+	return r
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
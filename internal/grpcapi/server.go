@@ -0,0 +1,189 @@
+package grpcapi
+
+//go:generate protoc --proto_path=../../proto --go_out=../.. --go_opt=module=BigDataForge --go-grpc_out=../.. --go-grpc_opt=module=BigDataForge plans/v1/plans.proto
+
+import (
+	"context"
+	"net/http"
+
+	"BigDataForge/internal/auth"
+	"BigDataForge/internal/changefeed"
+	"BigDataForge/internal/elastic"
+	"BigDataForge/internal/grpcapi/planspb"
+	"BigDataForge/internal/models"
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/ratelimit"
+	"BigDataForge/internal/schemas"
+	"BigDataForge/internal/services"
+	"BigDataForge/internal/tenancy"
+	"BigDataForge/internal/validators"
+
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Shutdown stops the server gracefully: it stops accepting calls and waits
+// for running ones. Calls still running when ctx ends, such as WatchPlans
+// streams, are cancelled.
+func Shutdown(ctx context.Context, server *grpc.Server) error {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		server.Stop()
+		return ctx.Err()
+	}
+}
+
+// NewServer builds a gRPC server exposing the plan API. Calls are authenticated,
+// resolved to org memberships and rate limited like the /api/v1 routes.
+func NewServer(redisClient *redis.Client, esFactory *elastic.Factory, schemaRegistry *schemas.Registry, authenticator auth.Authenticator, resolver *tenancy.Resolver, rateLimits ratelimit.Policies, changeFeed *services.ChangeFeedService) *grpc.Server {
+	guard := &guard{
+		authenticator: authenticator,
+		resolver:      resolver,
+		limiter:       ratelimit.NewLimiter(redisClient),
+		rateLimits:    rateLimits,
+	}
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(guard.unary),
+		grpc.ChainStreamInterceptor(guard.stream),
+	)
+	planspb.RegisterPlanServiceServer(server, NewPlanServer(redisClient, esFactory, schemaRegistry, changeFeed))
+	return server
+}
+
+// PlanServer implements planspb.PlanServiceServer on top of the same plan
+// service, schemas and business rules as the REST handlers.
+type PlanServer struct {
+	planspb.UnimplementedPlanServiceServer
+
+	plans     *services.PlanService
+	changes   *services.ChangeFeedService
+	validator *validators.PlanValidator
+}
+
+func NewPlanServer(redisClient *redis.Client, esFactory *elastic.Factory, schemaRegistry *schemas.Registry, changeFeed *services.ChangeFeedService) *PlanServer {
+	return &PlanServer{
+		plans:     services.NewPlanService(redisClient, esFactory),
+		changes:   changeFeed,
+		validator: validators.NewPlanValidator(schemaRegistry),
+	}
+}
+
+// Helper to validate a plan message like a REST body and decode it. It
+// returns the plan and the schema version it was validated against.
+func (server *PlanServer) validatePlan(ctx context.Context, plan *planspb.Plan, schemaName string) (models.Plan, string, error) {
+	if plan == nil {
+		return models.Plan{}, "", problems.NewError(http.StatusBadRequest, problems.CodeInvalidRequest, "plan is required")
+	}
+	body, err := planDocument(plan, schemaName == schemas.PlanSchema)
+	if err != nil {
+		return models.Plan{}, "", err
+	}
+	return server.validator.Validate(ctx, schemaName, body)
+}
+
+func (server *PlanServer) CreatePlan(ctx context.Context, req *planspb.CreatePlanRequest) (*planspb.CreatePlanResponse, error) {
+	plan, schemaVersion, err := server.validatePlan(ctx, req.GetPlan(), schemas.PlanSchema)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	eTag, err := server.plans.Create(ctx, plan, schemaVersion)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &planspb.CreatePlanResponse{PlanId: plan.ObjectID, Etag: eTag}, nil
+}
+
+func (server *PlanServer) GetPlan(ctx context.Context, req *planspb.GetPlanRequest) (*planspb.GetPlanResponse, error) {
+	stored, err := server.plans.Get(ctx, req.GetId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if req.GetIfNoneMatch() == stored.ETag {
+		return &planspb.GetPlanResponse{Etag: stored.ETag, NotModified: true}, nil
+	}
+
+	plan, err := planToProto(stored.Plan)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &planspb.GetPlanResponse{Plan: plan, Etag: stored.ETag, SchemaVersion: stored.SchemaVersion}, nil
+}
+
+func (server *PlanServer) UpdatePlan(ctx context.Context, req *planspb.UpdatePlanRequest) (*planspb.UpdatePlanResponse, error) {
+	plan, schemaVersion, err := server.validatePlan(ctx, req.GetPlan(), schemas.PlanSchema)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	eTag, err := server.plans.Update(ctx, plan, schemaVersion)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &planspb.UpdatePlanResponse{PlanId: plan.ObjectID, Etag: eTag}, nil
+}
+
+func (server *PlanServer) PatchPlan(ctx context.Context, req *planspb.PatchPlanRequest) (*planspb.PatchPlanResponse, error) {
+	plan, schemaVersion, err := server.validatePlan(ctx, req.GetPlan(), schemas.PatchPlanSchema)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	eTag, err := server.plans.Patch(ctx, req.GetId(), plan, req.GetIfMatch(), schemaVersion)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &planspb.PatchPlanResponse{PlanId: req.GetId(), Etag: eTag}, nil
+}
+
+func (server *PlanServer) DeletePlan(ctx context.Context, req *planspb.DeletePlanRequest) (*emptypb.Empty, error) {
+	if err := server.plans.Delete(ctx, req.GetId()); err != nil {
+		return nil, statusError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (server *PlanServer) SearchPlans(ctx context.Context, req *planspb.SearchPlansRequest) (*planspb.SearchPlansResponse, error) {
+	result, err := server.plans.Search(ctx, models.SearchRequest{Key: req.GetKey(), Value: req.GetValue()})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	total, hits := services.SearchHits(result)
+	response := &planspb.SearchPlansResponse{Total: total}
+	for _, hit := range hits {
+		searchHit := &planspb.SearchHit{Id: hit.ID, Score: hit.Score}
+		if hit.Source != nil {
+			if searchHit.Source, err = structpb.NewStruct(hit.Source); err != nil {
+				return nil, statusError(err)
+			}
+		}
+		response.Hits = append(response.Hits, searchHit)
+	}
+	return response, nil
+}
+
+func (server *PlanServer) WatchPlans(req *planspb.WatchPlansRequest, stream planspb.PlanService_WatchPlansServer) error {
+	watch := services.WatchRequest{
+		Org:             req.GetOrg(),
+		IncludeDocument: req.GetIncludeDocument(),
+		LastEventID:     req.GetLastEventId(),
+	}
+	err := server.changes.Watch(stream.Context(), watch, func(event changefeed.Event) error {
+		message, err := eventToProto(event)
+		if err != nil {
+			return err
+		}
+		return stream.Send(message)
+	})
+	if err != nil {
+		return statusError(err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"BigDataForge/internal/elastic"
+	"BigDataForge/internal/schemas"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Redis pings the server.
+func Redis(client *redis.Client) Check {
+	return func(ctx context.Context) (map[string]interface{}, error) {
+		stats := client.PoolStats()
+		details := map[string]interface{}{"totalConns": stats.TotalConns, "idleConns": stats.IdleConns}
+		return details, client.Ping(ctx).Err()
+	}
+}
+
+// Elasticsearch reads the cluster health, bypassing the circuit breaker so
+// that the check sees the cluster recover; a red cluster fails the check.
+func Elasticsearch(factory *elastic.Factory) Check {
+	return func(ctx context.Context) (map[string]interface{}, error) {
+		client, err := factory.Client()
+		if err != nil {
+			return nil, err
+		}
+		res, err := client.Cluster.Health(client.Cluster.Health.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			return nil, fmt.Errorf("cluster health returned %s", res.Status())
+		}
+
+		var health struct {
+			Status        string `json:"status"`
+			NumberOfNodes int    `json:"number_of_nodes"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&health); err != nil {
+			return nil, err
+		}
+		details := map[string]interface{}{"cluster": health.Status, "nodes": health.NumberOfNodes, "breaker": factory.Breaker.State()}
+		if health.Status == "red" {
+			return details, errors.New("cluster is red")
+		}
+		return details, nil
+	}
+}
+
+// Schemas compiles the active version of every plan schema.
+func Schemas(registry *schemas.Registry) Check {
+	return func(ctx context.Context) (map[string]interface{}, error) {
+		details := map[string]interface{}{}
+		for _, name := range registry.Names() {
+			_, version, err := registry.Active(ctx, name)
+			if err != nil {
+				return details, fmt.Errorf("schema %s: %w", name, err)
+			}
+			details[name] = version
+		}
+		return details, nil
+	}
+}
+
+// Stopping fails once ctx is cancelled, so that load balancers stop sending
+// requests while the binary shuts down.
+func Stopping(ctx context.Context) Check {
+	return func(context.Context) (map[string]interface{}, error) {
+		if ctx.Err() != nil {
+			return nil, errors.New("shutting down")
+		}
+		return nil, nil
+	}
+}
@@ -0,0 +1,141 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// A binary is ready when its critical dependencies are. The other checks only
+// degrade it, for dependencies whose outage the binary rides out (the API
+// keeps accepting writes into the outbox while RabbitMQ is down, for example).
+
+// Statuses of a report and of its checks
+const (
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+	StatusFailing     = "failing"
+)
+
+// checkTimeout bounds each check, so a hanging dependency cannot stall probes.
+const checkTimeout = 2 * time.Second
+
+// Check reports the state of a dependency: details for operators, which may
+// be nil, and an error when the dependency is not usable.
+type Check func(ctx context.Context) (map[string]interface{}, error)
+
+// Checker runs the registered checks.
+type Checker struct {
+	started time.Time
+
+	mu     sync.RWMutex
+	checks map[string]check
+}
+
+type check struct {
+	critical bool
+	run      Check
+}
+
+func NewChecker() *Checker {
+	return &Checker{started: time.Now(), checks: map[string]check{}}
+}
+
+// Add registers a check. A failing critical check makes the binary unavailable.
+func (c *Checker) Add(name string, critical bool, run Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check{critical: critical, run: run}
+}
+
+// Report is the outcome of every check.
+type Report struct {
+	Status        string            `json:"status"`
+	UptimeSeconds int64             `json:"uptimeSeconds,omitempty"`
+	Checks        map[string]Result `json:"checks"`
+}
+
+// Result is the outcome of one check.
+type Result struct {
+	Status    string                 `json:"status"`
+	Critical  bool                   `json:"critical,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+	LatencyMs int64                  `json:"latencyMs,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// Run runs the checks concurrently.
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := make(map[string]check, len(c.checks))
+	for name, registered := range c.checks {
+		checks[name] = registered
+	}
+	c.mu.RUnlock()
+
+	report := Report{
+		Status:        StatusOK,
+		UptimeSeconds: int64(time.Since(c.started).Seconds()),
+		Checks:        make(map[string]Result, len(checks)),
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, registered := range checks {
+		wg.Add(1)
+		go func(name string, registered check) {
+			defer wg.Done()
+			result := runCheck(ctx, registered)
+			mu.Lock()
+			report.Checks[name] = result
+			mu.Unlock()
+		}(name, registered)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		switch {
+		case result.Status == StatusOK:
+		case result.Critical:
+			report.Status = StatusUnavailable
+		case report.Status == StatusOK:
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// Helper to run one check within checkTimeout
+func runCheck(ctx context.Context, registered check) Result {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	started := time.Now()
+	details, err := registered.run(ctx)
+	result := Result{
+		Status:    StatusOK,
+		Critical:  registered.critical,
+		LatencyMs: time.Since(started).Milliseconds(),
+		Details:   details,
+	}
+	if err != nil {
+		result.Status = StatusFailing
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Ready reports whether no critical check failed.
+func (r Report) Ready() bool {
+	return r.Status != StatusUnavailable
+}
+
+// Summary leaves out errors and details, which can name internal hosts, for
+// unauthenticated probes.
+func (r Report) Summary() Report {
+	summary := Report{Status: r.Status, Checks: make(map[string]Result, len(r.Checks))}
+	for name, result := range r.Checks {
+		summary.Checks[name] = Result{Status: result.Status, Critical: result.Critical}
+	}
+	return summary
+}
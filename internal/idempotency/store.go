@@ -0,0 +1,103 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Header carries the client-chosen idempotency key.
+const Header = "Idempotency-Key"
+
+// Records live under idempotency:<scope>:<key>. A key is first reserved as
+// pending for a short time; once the request finishes the response replaces
+// the reservation and is kept for the configured TTL.
+const pendingTTL = time.Minute
+
+var (
+	// ErrInProgress means another request with the same key has not finished yet.
+	ErrInProgress = errors.New("request with this idempotency key is in progress")
+	// ErrPayloadMismatch means the key was used before with a different request body.
+	ErrPayloadMismatch = errors.New("idempotency key reused with a different payload")
+)
+
+// Response is a stored response replayed to retries.
+type Response struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers"`
+	Body    []byte      `json:"body"`
+}
+
+type record struct {
+	BodyHash string    `json:"bodyHash"`
+	Response *Response `json:"response,omitempty"`
+}
+
+// Store keeps idempotency records in Redis.
+type Store struct {
+	redisClient *redis.Client
+	TTL         time.Duration
+}
+
+func NewStore(redisClient *redis.Client, ttl time.Duration) *Store {
+	return &Store{redisClient: redisClient, TTL: ttl}
+}
+
+func recordKey(scope, key string) string {
+	return "idempotency:" + scope + ":" + key
+}
+
+// Begin reserves a key for a request body. It returns the stored response
+// when the key already completed with the same body, ErrInProgress while the
+// first request is running and ErrPayloadMismatch for a different body.
+func (s *Store) Begin(ctx context.Context, scope, key, bodyHash string) (*Response, error) {
+	pending, err := json.Marshal(record{BodyHash: bodyHash})
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := s.redisClient.SetNX(ctx, recordKey(scope, key), pending, pendingTTL).Result()
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	data, err := s.redisClient.Get(ctx, recordKey(scope, key)).Bytes()
+	if err == redis.Nil {
+		// The record expired in between; let the caller retry as a new request
+		return nil, ErrInProgress
+	}
+	if err != nil {
+		return nil, err
+	}
+	var existing record
+	if err := json.Unmarshal(data, &existing); err != nil {
+		return nil, err
+	}
+	if existing.BodyHash != bodyHash {
+		return nil, ErrPayloadMismatch
+	}
+	if existing.Response == nil {
+		return nil, ErrInProgress
+	}
+	return existing.Response, nil
+}
+
+// Complete stores the response of a reserved key.
+func (s *Store) Complete(ctx context.Context, scope, key, bodyHash string, response *Response) error {
+	data, err := json.Marshal(record{BodyHash: bodyHash, Response: response})
+	if err != nil {
+		return err
+	}
+	return s.redisClient.Set(ctx, recordKey(scope, key), data, s.TTL).Err()
+}
+
+// Release drops a reservation so the request can be retried.
+func (s *Store) Release(ctx context.Context, scope, key string) error {
+	return s.redisClient.Del(ctx, recordKey(scope, key)).Err()
+}
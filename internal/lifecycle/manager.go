@@ -0,0 +1,183 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// A Manager starts the components of a binary and, on SIGINT or SIGTERM or
+// when a component fails, stops them in the reverse order they were
+// registered, like deferred calls: register clients first and servers last,
+// so that servers drain before the clients they use are closed. All stop
+// steps share one shutdown deadline; a second signal exits immediately.
+
+// Manager runs components and shuts them down in order.
+type Manager struct {
+	timeout time.Duration
+
+	// stopping is cancelled when the shutdown starts
+	stopping context.Context
+	stop     context.CancelFunc
+
+	mu     sync.Mutex
+	steps  []step
+	failed chan error
+}
+
+type step struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// New returns a manager giving the shutdown timeout to finish.
+func New(timeout time.Duration) *Manager {
+	stopping, stop := context.WithCancel(context.Background())
+	return &Manager{timeout: timeout, stopping: stopping, stop: stop, failed: make(chan error, 1)}
+}
+
+// Stopping returns a context that is cancelled when the shutdown starts.
+func (m *Manager) Stopping() context.Context {
+	return m.stopping
+}
+
+// OnStop registers a stop step.
+func (m *Manager) OnStop(name string, stop func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.steps = append(m.steps, step{name: name, stop: stop})
+}
+
+// Close registers a step closing a client.
+func (m *Manager) Close(name string, close func() error) {
+	m.OnStop(name, func(context.Context) error { return close() })
+}
+
+// Run runs a background loop with a context that its stop step cancels; the
+// step then waits for run to return.
+func (m *Manager) Run(name string, run func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		run(ctx)
+	}()
+	m.OnStop(name, func(stopCtx context.Context) error {
+		cancel()
+		select {
+		case <-done:
+			return nil
+		case <-stopCtx.Done():
+			return stopCtx.Err()
+		}
+	})
+}
+
+// Serve runs a server. serve must return nil, or http.ErrServerClosed, once
+// shutdown was called; any other return starts the shutdown.
+func (m *Manager) Serve(name string, serve func() error, shutdown func(ctx context.Context) error) {
+	go func() {
+		if err := serve(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			m.fail(fmt.Errorf("%s: %w", name, err))
+		}
+	}()
+	m.OnStop(name, shutdown)
+}
+
+// ServeHTTP runs an HTTP server. Shutdown stops accepting connections and
+// waits for in-flight requests; connections still open at the deadline are
+// closed. Request contexts carry Stopping for long-lived streams (see
+// UntilShutdown).
+func (m *Manager) ServeHTTP(name string, server *http.Server) {
+	server.BaseContext = func(net.Listener) context.Context {
+		return context.WithValue(context.Background(), stoppingKey{}, m.stopping)
+	}
+	m.Serve(name, server.ListenAndServe, func(ctx context.Context) error {
+		if err := server.Shutdown(ctx); err != nil {
+			server.Close()
+			return err
+		}
+		return nil
+	})
+}
+
+// Helper to report a failed component; the first failure starts the shutdown
+func (m *Manager) fail(err error) {
+	select {
+	case m.failed <- err:
+	default:
+	}
+}
+
+// Wait blocks until a signal arrives or a component fails, then runs the stop
+// steps. It returns the failure, or the errors of the stop steps.
+func (m *Manager) Wait() error {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	var failure error
+	select {
+	case sig := <-signals:
+		slog.Info("Shutting down", "signal", sig.String(), "timeout", m.timeout.String())
+	case failure = <-m.failed:
+		slog.Error("Shutting down after a failure", "error", failure)
+	}
+	go func() {
+		sig := <-signals
+		log.Fatalf("Received %s again, exiting without finishing the shutdown", sig)
+	}()
+
+	return errors.Join(failure, m.Shutdown())
+}
+
+// Shutdown runs the stop steps in reverse order within the shutdown timeout.
+// A failing step does not keep the later ones from running.
+func (m *Manager) Shutdown() error {
+	m.stop()
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	m.mu.Lock()
+	steps := m.steps
+	m.steps = nil
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(steps) - 1; i >= 0; i-- {
+		started := time.Now()
+		if err := steps[i].stop(ctx); err != nil {
+			slog.Error("Failed to stop component", "component", steps[i].name, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", steps[i].name, err))
+			continue
+		}
+		slog.Info("Stopped component", "component", steps[i].name, "durationMs", time.Since(started).Milliseconds())
+	}
+	return errors.Join(errs...)
+}
+
+type stoppingKey struct{}
+
+// UntilShutdown returns a context that is also cancelled when the server
+// serving the request starts shutting down. Streams that clients resume, such
+// as the change feed, end early with it instead of holding up the drain.
+func UntilShutdown(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	if stopping, ok := ctx.Value(stoppingKey{}).(context.Context); ok {
+		stopAfter := context.AfterFunc(stopping, cancel)
+		return ctx, func() {
+			stopAfter()
+			cancel()
+		}
+	}
+	return ctx, cancel
+}
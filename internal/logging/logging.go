@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log"
+	"log/slog"
+
+	"BigDataForge/internal/config"
+	"BigDataForge/internal/requestid"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Both binaries log through log/slog. Records logged with a context carry its
+// request ID and trace, so the API's log lines of a write and the listener's
+// lines of the resulting message can be found together. Payloads are only
+// logged at debug level, and only through Payload, which redacts them.
+
+// Setup installs the default logger. Messages of the standard log package,
+// which is only left for fatal startup errors, are logged at error level.
+func Setup(cfg config.Logging, w io.Writer) {
+	var level slog.Level
+	_ = level.UnmarshalText([]byte(cfg.Level))
+	options := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	if cfg.Format == config.LogText {
+		handler = slog.NewTextHandler(w, options)
+	} else {
+		handler = slog.NewJSONHandler(w, options)
+	}
+	log.SetFlags(0) // the handler adds the time
+	slog.SetDefault(slog.New(contextHandler{handler}))
+	slog.SetLogLoggerLevel(slog.LevelError)
+}
+
+// contextHandler adds the request ID and trace of a record's context.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := requestid.FromContext(ctx); id != "" {
+		record.AddAttrs(slog.String("requestId", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("traceId", span.TraceID().String()), slog.String("spanId", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
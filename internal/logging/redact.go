@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"encoding/json"
+	"log/slog"
+)
+
+// Redacted replaces values that may hold member data.
+const Redacted = "[REDACTED]"
+
+// Rules name the fields of a payload whose values are logged as they are.
+// Every other scalar value is replaced with Redacted, at any depth, so fields
+// added to a schema later are hidden until they are listed here. Objects and
+// arrays are walked, so the shape of the payload stays visible.
+type Rules map[string]bool
+
+// PlanRules keep the identifiers and types of plan documents and their child
+// objects, and hide names, cost shares, dates and search terms.
+var PlanRules = Rules{
+	"objectId":   true,
+	"objectType": true,
+	"_org":       true,
+	"planType":   true,
+	"plan_join":  true,
+}
+
+// Redact returns a copy of a decoded JSON value with the values of fields not
+// kept by the rules replaced.
+func (r Rules) Redact(value interface{}) interface{} {
+	return r.redact(value, false)
+}
+
+// Helper to redact a value; keep is set below a kept field
+func (r Rules) redact(value interface{}, keep bool) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(value))
+		for field, child := range value {
+			redacted[field] = r.redact(child, keep || r[field])
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(value))
+		for i, child := range value {
+			redacted[i] = r.redact(child, keep)
+		}
+		return redacted
+	default:
+		if keep || value == nil {
+			return value
+		}
+		return Redacted
+	}
+}
+
+// Payload logs a JSON document redacted by rules. The document is only
+// decoded when the record is logged, so debug payloads cost nothing otherwise.
+func Payload(rules Rules, document []byte) slog.LogValuer {
+	return payload{rules: rules, document: document}
+}
+
+type payload struct {
+	rules    Rules
+	document []byte
+}
+
+func (p payload) LogValue() slog.Value {
+	var value interface{}
+	if err := json.Unmarshal(p.document, &value); err != nil {
+		return slog.StringValue("[invalid JSON]")
+	}
+	return slog.AnyValue(p.rules.Redact(value))
+}
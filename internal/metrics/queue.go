@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"log/slog"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/streadway/amqp"
+)
+
+var (
+	queueMessagesDesc = prometheus.NewDesc(namespace+"_queue_messages",
+		"Messages ready for delivery in the queue, from a passive declare at scrape time.", []string{"queue"}, nil)
+	queueConsumersDesc = prometheus.NewDesc(namespace+"_queue_consumers",
+		"Consumers of the queue, from a passive declare at scrape time.", []string{"queue"}, nil)
+)
+
+// QueueCollector reports the depth of queues. inspect passively declares a
+// queue, which closes the channel it runs on when the queue is missing, so it
+// should use a channel of its own.
+type QueueCollector struct {
+	queues  []string
+	inspect func(queue string) (amqp.Queue, error)
+}
+
+func NewQueueCollector(inspect func(queue string) (amqp.Queue, error), queues ...string) *QueueCollector {
+	return &QueueCollector{queues: queues, inspect: inspect}
+}
+
+func (c *QueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueMessagesDesc
+	ch <- queueConsumersDesc
+}
+
+func (c *QueueCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, queue := range c.queues {
+		q, err := c.inspect(queue)
+		if err != nil {
+			slog.Warn("Failed to inspect queue for metrics", "queue", queue, "error", err)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(queueMessagesDesc, prometheus.GaugeValue, float64(q.Messages), queue)
+		ch <- prometheus.MustNewConstMetric(queueConsumersDesc, prometheus.GaugeValue, float64(q.Consumers), queue)
+	}
+}
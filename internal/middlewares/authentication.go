@@ -1,42 +1,37 @@
 package middlewares
 
 import (
-	"context"
+	"errors"
+	"log/slog"
 	"net/http"
-	"os"
-	"strings"
+
+	"BigDataForge/internal/auth"
+	"BigDataForge/internal/problems"
 
 	"github.com/gin-gonic/gin"
-	"google.golang.org/api/idtoken"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+func AuthMiddleware(authenticator auth.Authenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 
-		googleClientID := os.Getenv("GOOGLE_CLIENT_ID")
-
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header missing"})
+		principal, err := authenticator.Authenticate(c.Request)
+		if errors.Is(err, auth.ErrNoCredentials) {
+			problems.Abort(c, http.StatusUnauthorized, problems.CodeUnauthorized, "No supported credentials provided")
 			return
 		}
-
-		// Extract Bearer token from the Authorization header
-		bearerToken := strings.TrimPrefix(authHeader, "Bearer ")
-		if bearerToken == authHeader {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Bearer token missing"})
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			problems.Abort(c, http.StatusUnauthorized, problems.CodeUnauthorized, err.Error())
 			return
 		}
-
-		// Validate the token using Google ID Token validator
-		payload, err := idtoken.Validate(context.Background(), bearerToken, googleClientID)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token", "details": err.Error()})
+			slog.ErrorContext(c.Request.Context(), "Authentication failed", "error", err)
+			problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to authenticate request")
 			return
 		}
 
-		// Add user claims to the context if token is valid
-		c.Set("userPayload", payload.Claims)
+		// Add the principal and its claims to the context
+		c.Set(auth.PrincipalKey, principal)
+		c.Set("userPayload", principal.Claims)
 		c.Next()
 	}
 }
@@ -0,0 +1,136 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"BigDataForge/internal/idempotency"
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/requestid"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Largest request body hashed for an Idempotency-Key
+const idempotentMaxBody = 10 << 20
+
+// Response headers that describe the current request rather than the stored response
+var unreplayedHeaders = map[string]bool{
+	requestid.Header: true,
+	"Date":           true,
+	"Retry-After":    true,
+}
+
+// responseRecorder keeps a copy of everything a handler writes.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware replays the first response to retries that carry the
+// same Idempotency-Key and payload. Keys are scoped to the caller, and the
+// payload covers the method, URI and body. Server errors are not stored so
+// they can be retried. The outcome is recorded even when the client went away
+// meanwhile, since that is when it retries.
+func IdempotencyMiddleware(store *idempotency.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotency.Header)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > 255 {
+			problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Idempotency-Key must be at most 255 characters")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, idempotentMaxBody))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			problems.Abort(c, http.StatusRequestEntityTooLarge, problems.CodeInvalidRequest, "Request body is too large")
+			return
+		}
+		if err != nil {
+			problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Failed to read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		io.WriteString(hash, c.Request.Method+" "+c.Request.URL.RequestURI()+"\n")
+		hash.Write(body)
+		bodyHash := hex.EncodeToString(hash.Sum(nil))
+		scope := rateLimitKey(c)
+		ctx := c.Request.Context()
+
+		stored, err := store.Begin(ctx, scope, key, bodyHash)
+		switch {
+		case errors.Is(err, idempotency.ErrPayloadMismatch):
+			problems.Abort(c, http.StatusUnprocessableEntity, problems.CodeIdempotencyReused, "Idempotency-Key was already used with a different request")
+			return
+		case errors.Is(err, idempotency.ErrInProgress):
+			c.Header("Retry-After", "1")
+			problems.Abort(c, http.StatusConflict, problems.CodeConflict, "A request with this Idempotency-Key is still in progress")
+			return
+		case err != nil:
+			// Fail open like the rate limiter; the request runs without replay protection
+			slog.WarnContext(ctx, "Idempotency store unavailable", "error", err)
+			c.Next()
+			return
+		case stored != nil:
+			for name, values := range stored.Headers {
+				for _, value := range values {
+					c.Writer.Header().Add(name, value)
+				}
+			}
+			c.Header("Idempotent-Replayed", "true")
+			c.Status(stored.Status)
+			c.Writer.Write(stored.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// Record the outcome even if the client cancelled the request
+		ctx = context.WithoutCancel(ctx)
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			if err := store.Release(ctx, scope, key); err != nil {
+				slog.ErrorContext(ctx, "Failed to release idempotency key", "error", err)
+			}
+			return
+		}
+
+		headers := http.Header{}
+		for name, values := range recorder.Header() {
+			if !unreplayedHeaders[name] && !strings.HasPrefix(name, "Ratelimit-") {
+				headers[name] = values
+			}
+		}
+		response := &idempotency.Response{Status: status, Headers: headers, Body: recorder.body.Bytes()}
+		if err := store.Complete(ctx, scope, key, bodyHash, response); err != nil {
+			slog.ErrorContext(ctx, "Failed to store idempotent response", "error", err)
+		}
+	}
+}
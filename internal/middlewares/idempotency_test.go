@@ -0,0 +1,162 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"BigDataForge/internal/idempotency"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+// Helper to serve a counting create handler behind IdempotencyMiddleware
+func idempotentRouter(t *testing.T, handler gin.HandlerFunc) (*gin.Engine, *idempotency.Store) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	server := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+	store := idempotency.NewStore(redisClient, time.Hour)
+
+	router := gin.New()
+	router.POST("/plans", IdempotencyMiddleware(store), handler)
+	return router, store
+}
+
+func idempotentRequest(ctx context.Context, key, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/plans", strings.NewReader(body)).WithContext(ctx)
+	req.Header.Set(idempotency.Header, key)
+	req.RemoteAddr = "192.0.2.1:1234"
+	return req
+}
+
+func TestIdempotencyReplaysResponse(t *testing.T) {
+	var calls atomic.Int32
+	router, _ := idempotentRouter(t, func(c *gin.Context) {
+		calls.Add(1)
+		c.Header("ETag", `"v1"`)
+		c.JSON(http.StatusCreated, gin.H{"objectId": "p1"})
+	})
+
+	first := httptest.NewRecorder()
+	router.ServeHTTP(first, idempotentRequest(context.Background(), "k1", `{"objectId":"p1"}`))
+	retry := httptest.NewRecorder()
+	router.ServeHTTP(retry, idempotentRequest(context.Background(), "k1", `{"objectId":"p1"}`))
+
+	if calls.Load() != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls.Load())
+	}
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Errorf("retry = %d %s, want %d %s", retry.Code, retry.Body, first.Code, first.Body)
+	}
+	if retry.Header().Get("Idempotent-Replayed") != "true" || retry.Header().Get("ETag") != `"v1"` {
+		t.Errorf("retry headers = %v, want the stored ETag and Idempotent-Replayed", retry.Header())
+	}
+}
+
+func TestIdempotencyRejectsDifferentPayload(t *testing.T) {
+	var calls atomic.Int32
+	router, _ := idempotentRouter(t, func(c *gin.Context) {
+		calls.Add(1)
+		c.Status(http.StatusCreated)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), idempotentRequest(context.Background(), "k1", `{"objectId":"p1"}`))
+	reused := httptest.NewRecorder()
+	router.ServeHTTP(reused, idempotentRequest(context.Background(), "k1", `{"objectId":"p2"}`))
+
+	if reused.Code != http.StatusUnprocessableEntity || !strings.Contains(reused.Body.String(), "idempotency_key_reused") {
+		t.Errorf("reused key = %d %s, want 422 idempotency_key_reused", reused.Code, reused.Body)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("handler ran %d times, want 1", calls.Load())
+	}
+}
+
+func TestIdempotencyRejectsConcurrentRequest(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	router, _ := idempotentRouter(t, func(c *gin.Context) {
+		close(started)
+		<-release
+		c.Status(http.StatusCreated)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		router.ServeHTTP(httptest.NewRecorder(), idempotentRequest(context.Background(), "k1", `{}`))
+	}()
+	<-started
+
+	concurrent := httptest.NewRecorder()
+	router.ServeHTTP(concurrent, idempotentRequest(context.Background(), "k1", `{}`))
+	close(release)
+	<-done
+
+	if concurrent.Code != http.StatusConflict || concurrent.Header().Get("Retry-After") == "" {
+		t.Errorf("concurrent request = %d %v, want 409 with Retry-After", concurrent.Code, concurrent.Header())
+	}
+}
+
+func TestIdempotencyStoresResponseOfCancelledClient(t *testing.T) {
+	// The client gives up while the write is running
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	router, _ := idempotentRouter(t, func(c *gin.Context) {
+		calls.Add(1)
+		cancel()
+		c.JSON(http.StatusCreated, gin.H{"objectId": "p1"})
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), idempotentRequest(ctx, "k1", `{"objectId":"p1"}`))
+
+	retry := httptest.NewRecorder()
+	router.ServeHTTP(retry, idempotentRequest(context.Background(), "k1", `{"objectId":"p1"}`))
+	if retry.Code != http.StatusCreated || retry.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("retry = %d %v, want the replayed 201", retry.Code, retry.Header())
+	}
+	if calls.Load() != 1 {
+		t.Errorf("handler ran %d times, want 1", calls.Load())
+	}
+}
+
+func TestIdempotencyReleasesKeyOfCancelledServerError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	router, _ := idempotentRouter(t, func(c *gin.Context) {
+		if calls.Add(1) == 1 {
+			cancel()
+			c.Status(http.StatusServiceUnavailable)
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), idempotentRequest(ctx, "k1", `{}`))
+
+	retry := httptest.NewRecorder()
+	router.ServeHTTP(retry, idempotentRequest(context.Background(), "k1", `{}`))
+	if retry.Code != http.StatusCreated || calls.Load() != 2 {
+		t.Errorf("retry = %d after %d calls, want 201 from a second run", retry.Code, calls.Load())
+	}
+}
+
+func TestIdempotencyLimitsBody(t *testing.T) {
+	router, _ := idempotentRouter(t, func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, idempotentRequest(context.Background(), "k1", strings.Repeat("x", idempotentMaxBody+1)))
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized body = %d, want 413", recorder.Code)
+	}
+}
@@ -0,0 +1,33 @@
+package middlewares
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoggingMiddleware logs each request once it completed, with its request ID.
+// Query strings and bodies are left out, as they may hold member data; server
+// errors are logged at error level.
+func LoggingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		started := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		slog.Log(c.Request.Context(), level, "Request completed",
+			"method", c.Request.Method,
+			"route", c.FullPath(),
+			"path", c.Request.URL.Path,
+			"status", status,
+			"durationMs", time.Since(started).Milliseconds(),
+			"clientIp", c.ClientIP(),
+		)
+	}
+}
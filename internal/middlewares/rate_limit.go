@@ -0,0 +1,60 @@
+package middlewares
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"BigDataForge/internal/auth"
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitMiddleware applies a policy per caller: the API key or signing key
+// when one was used, otherwise the authenticated subject, otherwise the client IP.
+// When several policies apply to a route, the headers describe the last one.
+func RateLimitMiddleware(limiter *ratelimit.Limiter, policy ratelimit.Policy) gin.HandlerFunc {
+	return rateLimit(limiter, policy, rateLimitKey)
+}
+
+// IPRateLimitMiddleware applies a policy per client IP. It runs before
+// AuthMiddleware, so it also bounds requests with missing or invalid credentials.
+func IPRateLimitMiddleware(limiter *ratelimit.Limiter, policy ratelimit.Policy) gin.HandlerFunc {
+	return rateLimit(limiter, policy, func(c *gin.Context) string {
+		return ratelimit.CallerKey(nil, c.ClientIP())
+	})
+}
+
+// Helper to take a token from the bucket of key(c) for each request
+func rateLimit(limiter *ratelimit.Limiter, policy ratelimit.Policy, key func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if policy.Disabled() {
+			c.Next()
+			return
+		}
+
+		result, err := limiter.Allow(c.Request.Context(), policy, key(c))
+		if err != nil {
+			// Fail open: an unavailable limiter must not take the API down
+			slog.WarnContext(c.Request.Context(), "Rate limiter unavailable", "error", err)
+			c.Next()
+			return
+		}
+
+		for name, value := range result.Headers(policy) {
+			c.Header(name, value)
+		}
+		if !result.Allowed {
+			problems.Abort(c, http.StatusTooManyRequests, problems.CodeRateLimited, fmt.Sprintf("Rate limit of %d requests per %s exceeded", policy.Limit, policy.Period))
+			return
+		}
+		c.Next()
+	}
+}
+
+// Helper to pick the bucket key of the caller
+func rateLimitKey(c *gin.Context) string {
+	return ratelimit.CallerKey(auth.FromContext(c), c.ClientIP())
+}
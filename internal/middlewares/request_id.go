@@ -0,0 +1,24 @@
+package middlewares
+
+import (
+	"BigDataForge/internal/requestid"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDMiddleware reuses the caller's X-Request-ID or assigns a new one,
+// and echoes it in the response. The request context carries it on to
+// service calls, log records and published events.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if id == "" || len(id) > 128 {
+			id = requestid.New()
+		}
+
+		c.Set(requestid.Key, id)
+		c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), id))
+		c.Header(requestid.Header, id)
+		c.Next()
+	}
+}
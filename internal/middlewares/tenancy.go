@@ -0,0 +1,45 @@
+package middlewares
+
+import (
+	"log/slog"
+	"net/http"
+
+	"BigDataForge/internal/auth"
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/tenancy"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TenancyMiddleware resolves the org memberships of the authenticated principal.
+// It must run after AuthMiddleware.
+func TenancyMiddleware(resolver *tenancy.Resolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := c.MustGet(auth.PrincipalKey).(*auth.Principal)
+		if !ok {
+			problems.Abort(c, http.StatusUnauthorized, problems.CodeUnauthorized, "Request is not authenticated")
+			return
+		}
+
+		tenant, err := resolver.Resolve(c.Request.Context(), principal)
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "Failed to resolve memberships", "subject", principal.Subject, "error", err)
+			problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to resolve organization memberships")
+			return
+		}
+
+		c.Set(tenancy.TenantKey, tenant)
+		c.Next()
+	}
+}
+
+// RequireRole only lets through principals holding at least role in org.
+func RequireRole(org string, role tenancy.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !tenancy.FromContext(c).Can(org, role) {
+			problems.Abort(c, http.StatusForbidden, problems.CodeForbidden, "Requires the "+string(role)+" role")
+			return
+		}
+		c.Next()
+	}
+}
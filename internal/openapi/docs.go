@@ -0,0 +1,10 @@
+package openapi
+
+import (
+	_ "embed"
+)
+
+// DocsPage renders the document served next to it (openapi.json) with Redoc.
+//
+//go:embed docs.html
+var DocsPage []byte
@@ -0,0 +1,235 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"BigDataForge/internal/audit"
+	"BigDataForge/internal/auth"
+	"BigDataForge/internal/changefeed"
+	"BigDataForge/internal/health"
+	"BigDataForge/internal/models"
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/resources"
+	"BigDataForge/internal/schemas"
+	"BigDataForge/internal/webhooks"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The document is generated from the routes registered on the router: every
+// route must have an entry in the operation table (operations.go), or in the
+// generated CRUD operations of schema-registered resources. Request and
+// response bodies come from the active plan schemas, the resource schemas and
+// the Go types the handlers encode; errors share the problem document model.
+
+// Build generates the OpenAPI document of the given routes. planSchemas holds
+// the active version of each plan schema by name. Routes without an operation
+// entry are left out of the document and returned as "METHOD /path".
+func Build(routes gin.RoutesInfo, planSchemas map[string][]byte, resourceList []*resources.Resource) (*Document, []string) {
+	entries := operations()
+	for _, resource := range resourceList {
+		for key, op := range resourceOperations(resource) {
+			entries[key] = op
+		}
+	}
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       "BigDataForge API",
+			Version:     "1",
+			Description: "Stores, validates and searches healthcare plans and schema-registered resources. Errors are RFC 7807 problem documents with a stable code.",
+		},
+		Tags:       tags,
+		Paths:      map[string]PathItem{},
+		Components: components(planSchemas, resourceList),
+		Security: []SecurityRequirement{
+			{"bearerAuth": {}},
+			{"apiKey": {}},
+			{"hmac": {}},
+		},
+	}
+
+	var undocumented []string
+	for _, route := range routes {
+		key := route.Method + " " + route.Path
+		entry, ok := entries[key]
+		if !ok {
+			undocumented = append(undocumented, key)
+			continue
+		}
+
+		op := *entry
+		op.Parameters = append(pathParameters(route.Path), entry.Parameters...)
+		op.Responses = commonResponses(route.Path, entry)
+
+		path := openAPIPath(route.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = &op
+	}
+	sort.Strings(undocumented)
+	return doc, undocumented
+}
+
+// Helper to convert a gin path ("/webhooks/:id") to an OpenAPI path ("/webhooks/{id}")
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Helper to declare the parameters of a gin path
+func pathParameters(path string) []Parameter {
+	var parameters []Parameter
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			parameters = append(parameters, Parameter{Name: segment[1:], In: "path", Required: true, Schema: Schema{"type": "string"}})
+		}
+	}
+	return parameters
+}
+
+// Helper to add the error responses of the middlewares guarding a path
+func commonResponses(path string, op *Operation) map[string]*Response {
+	responses := make(map[string]*Response, len(op.Responses)+4)
+	for status, response := range op.Responses {
+		responses[status] = response
+	}
+	if op.Security != nil && len(*op.Security) == 0 {
+		return responses
+	}
+
+	// Authentication, org resolution and rate limiting
+	responses[strconv.Itoa(http.StatusUnauthorized)] = responseRef("Unauthorized")
+	responses[strconv.Itoa(http.StatusTooManyRequests)] = responseRef("TooManyRequests")
+	responses[strconv.Itoa(http.StatusInternalServerError)] = responseRef("InternalError")
+	if strings.HasPrefix(path, "/api/v1/admin/") {
+		responses[strconv.Itoa(http.StatusForbidden)] = responseRef("Forbidden")
+	}
+	return responses
+}
+
+var tags = []Tag{
+	{Name: "Plans", Description: "Plans validated against the versioned plan schemas and business rules."},
+	{Name: "Resources", Description: "Documents of the resource types registered from RESOURCE_SCHEMA_DIR."},
+	{Name: "Webhooks", Description: "Deliveries of plan change events to partner endpoints."},
+	{Name: "GraphQL", Description: "The plan API as a GraphQL schema."},
+	{Name: "Admin", Description: "Schemas, machine credentials, memberships and the audit log. Requires the admin role of the global org."},
+	{Name: "Health", Description: "Probes and dependency status."},
+	{Name: "Documentation", Description: "This document."},
+}
+
+// Helper to build the shared schemas, responses and security schemes
+func components(planSchemas map[string][]byte, resourceList []*resources.Resource) Components {
+	problem := schemaOf(reflect.TypeOf(problems.Problem{}))
+	problem["properties"].(Schema)["code"] = Schema{"type": "string", "enum": problems.Codes}
+
+	componentSchemas := map[string]Schema{
+		"Plan":          jsonSchema(planSchemas[schemas.PlanSchema], "A plan, validated against the active version of the plan schema."),
+		"PlanPatch":     jsonSchema(planSchemas[schemas.PatchPlanSchema], "A partial plan, validated against the active version of the patch_plan schema."),
+		"Problem":       problem,
+		"SearchRequest": schemaOf(reflect.TypeOf(models.SearchRequest{})),
+		"SearchResult": {
+			"type":                 "object",
+			"description":          "The Elasticsearch search response. Matching child objects are returned as separate hits.",
+			"additionalProperties": true,
+		},
+		"ChangeEvent":     schemaOf(reflect.TypeOf(changefeed.Event{})),
+		"Webhook":         schemaOf(reflect.TypeOf(webhooks.Subscription{})),
+		"WebhookDelivery": schemaOf(reflect.TypeOf(webhooks.Attempt{})),
+		"APIKey":          schemaOf(reflect.TypeOf(auth.APIKey{})),
+		"HMACKey":         schemaOf(reflect.TypeOf(auth.HMACKey{})),
+		"AuditEntry":      schemaOf(reflect.TypeOf(audit.Entry{})),
+		"SchemaVersion":   schemaOf(reflect.TypeOf(schemas.Version{})),
+		"HealthReport":    schemaOf(reflect.TypeOf(health.Report{})),
+	}
+	for _, resource := range resourceList {
+		componentSchemas[resourceSchemaName(resource)] = jsonSchema(resource.RawSchema, "A "+resource.Name+" document.")
+		componentSchemas[resourceSchemaName(resource)+"Patch"] = patchSchema(resource.RawSchema, "A partial "+resource.Name+" document.")
+	}
+
+	problemContent := map[string]MediaType{problems.ContentType: {Schema: ref("Problem")}}
+	problemResponse := func(description string) *Response {
+		return &Response{Description: description, Content: problemContent}
+	}
+	rateLimited := problemResponse("Rate limit exceeded")
+	rateLimited.Headers = map[string]Header{
+		"Retry-After":      {Description: "Seconds until a request is allowed", Schema: Schema{"type": "integer"}},
+		"RateLimit-Limit":  {Schema: Schema{"type": "integer"}},
+		"RateLimit-Reset":  {Description: "Seconds until the bucket is full", Schema: Schema{"type": "integer"}},
+		"RateLimit-Policy": {Schema: Schema{"type": "string"}},
+	}
+	unavailable := problemResponse("Elasticsearch is unhealthy (503) or did not answer in time (504); try again later")
+	unavailable.Headers = map[string]Header{
+		"Retry-After": {Description: "Seconds until the circuit breaker lets a search through, while it is open", Schema: Schema{"type": "integer"}},
+	}
+
+	subscribersFull := problemResponse("The API streams to as many change feed subscribers as it can; try again later")
+	subscribersFull.Headers = map[string]Header{
+		"Retry-After": {Description: "Seconds to wait before subscribing again", Schema: Schema{"type": "integer"}},
+	}
+
+	return Components{
+		Schemas: componentSchemas,
+		Responses: map[string]*Response{
+			"BadRequest":           problemResponse("Invalid request, or a document failing schema validation (validation_failed) or business rules (business_rule_violation)"),
+			"Unauthorized":         problemResponse("Missing or invalid credentials"),
+			"Forbidden":            problemResponse("The caller lacks the required role"),
+			"NotFound":             problemResponse("Not found, or owned by an org the caller cannot view"),
+			"Conflict":             problemResponse("The object already exists, or a request with the same Idempotency-Key is in progress"),
+			"PreconditionFailed":   problemResponse("If-Match does not match the current ETag"),
+			"IdempotencyKeyReused": problemResponse("The Idempotency-Key was used with a different request"),
+			"TooManyRequests":      rateLimited,
+			"ServiceUnavailable":   unavailable,
+			"TooManySubscribers":   subscribersFull,
+			"EventsExpired":        problemResponse("Events after Last-Event-ID were trimmed from the change feed (events_expired); reload the plans and subscribe without it"),
+			"InternalError":        problemResponse("Internal error"),
+		},
+		SecuritySchemes: map[string]SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "Google, OIDC or dev-mode ID tokens, depending on AUTH_METHODS."},
+			"apiKey":     {Type: "apiKey", In: "header", Name: "X-API-Key", Description: "API keys issued through /api/v1/admin/apikeys. Also accepted as `Authorization: ApiKey <key>`."},
+			"hmac": {
+				Type: "apiKey", In: "header", Name: "Authorization",
+				Description: "`HMAC-SHA256 KeyId=<id>,Signature=<base64 HMAC-SHA256 of \"METHOD\\nREQUEST-URI\\nTIMESTAMP\\nhex(sha256(body))\">` with `X-Signature-Timestamp: <unix seconds>`.",
+			},
+		},
+	}
+}
+
+// Helper to embed a JSON Schema document as a component schema. The plan and
+// resource schemas only use keywords that draft-07 and 2020-12 share.
+func jsonSchema(raw []byte, description string) Schema {
+	var schema Schema
+	if err := json.Unmarshal(raw, &schema); err != nil || schema == nil {
+		return Schema{"type": "object", "description": description}
+	}
+	delete(schema, "$schema")
+	if _, ok := schema["description"]; !ok {
+		schema["description"] = description
+	}
+	return schema
+}
+
+// Helper to derive the patch schema of a resource the way the registry does:
+// any top-level property may be omitted
+func patchSchema(raw []byte, description string) Schema {
+	schema := jsonSchema(raw, description)
+	delete(schema, "required")
+	schema["description"] = description
+	return schema
+}
+
+func resourceSchemaName(resource *resources.Resource) string {
+	return strings.ToUpper(resource.Name[:1]) + resource.Name[1:]
+}
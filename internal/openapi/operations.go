@@ -0,0 +1,563 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"BigDataForge/internal/idempotency"
+	"BigDataForge/internal/resources"
+	"BigDataForge/internal/webhooks"
+)
+
+// Parameters and headers shared by several operations
+var (
+	ifMatch     = Parameter{Name: "If-Match", In: "header", Description: "ETag the object must still have; the write fails with 412 otherwise.", Schema: Schema{"type": "string"}}
+	ifNoneMatch = Parameter{Name: "If-None-Match", In: "header", Description: "ETag of a cached copy; 304 is returned while it is current.", Schema: Schema{"type": "string"}}
+	idempotent  = Parameter{Name: idempotency.Header, In: "header", Description: "Replays the stored response to retries of the same request (24h by default).", Schema: Schema{"type": "string", "maxLength": 255}}
+
+	etagHeaders = map[string]Header{"ETag": {Description: "Current version of the object", Schema: Schema{"type": "string"}}}
+)
+
+// Helper to declare a query parameter
+func query(name, description string, required bool) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Required: required, Schema: Schema{"type": "string"}}
+}
+
+// Helper to declare a required JSON request body
+func body(schema Schema) *RequestBody {
+	return &RequestBody{Required: true, Content: jsonContent(schema)}
+}
+
+// Helper to describe a JSON response
+func jsonResponse(description string, schema Schema) *Response {
+	return &Response{Description: description, Content: jsonContent(schema)}
+}
+
+// Helper to describe the {"message": ..., <id>: ...} bodies returned by writes
+func messageSchema(properties ...string) Schema {
+	props := Schema{"message": Schema{"type": "string"}}
+	for _, property := range properties {
+		props[property] = Schema{"type": "string"}
+	}
+	return Schema{"type": "object", "properties": props}
+}
+
+func arrayOf(schema Schema) Schema {
+	return Schema{"type": "array", "items": schema}
+}
+
+var noContent = &Response{Description: "No content"}
+
+// Helper to add the Idempotency-Key header and its error responses to a write
+func withIdempotency(op *Operation) *Operation {
+	op.Parameters = append(op.Parameters, idempotent)
+	op.Responses[strconv.Itoa(http.StatusConflict)] = responseRef("Conflict")
+	op.Responses[strconv.Itoa(http.StatusUnprocessableEntity)] = responseRef("IdempotencyKeyReused")
+	return op
+}
+
+// operations documents the fixed routes, keyed by method and gin path. Every
+// route registered in routes.SetupRoutes needs an entry here.
+func operations() map[string]*Operation {
+	public := []SecurityRequirement{}
+	planID := query("id", "objectId of the plan", true)
+	auditFilters := []Parameter{
+		query("planId", "Only entries of this plan", false),
+		query("actor", "Only entries of this caller ID (<method>:<subject>) or email", false),
+		{Name: "from", In: "query", Description: "Only entries at or after this time", Schema: Schema{"type": "string", "format": "date-time"}},
+		{Name: "to", In: "query", Description: "Only entries at or before this time", Schema: Schema{"type": "string", "format": "date-time"}},
+	}
+
+	return map[string]*Operation{
+		// Plans
+		"POST /api/v1/plans": withIdempotency(&Operation{
+			OperationID: "createPlan",
+			Summary:     "Create a plan",
+			Description: "Validates the plan against the active plan schema and the business rules. Requires the editor role in the plan's `_org`.",
+			Tags:        []string{"Plans"},
+			RequestBody: body(ref("Plan")),
+			Responses: map[string]*Response{
+				"201": {Description: "Plan created", Headers: etagHeaders, Content: jsonContent(messageSchema("planId"))},
+				"400": responseRef("BadRequest"),
+				"403": responseRef("Forbidden"),
+			},
+		}),
+		"GET /api/v1/plans": {
+			OperationID: "getPlan",
+			Summary:     "Fetch a plan",
+			Tags:        []string{"Plans"},
+			Parameters:  []Parameter{planID, ifNoneMatch},
+			Responses: map[string]*Response{
+				"200": {
+					Description: "The plan",
+					Headers: map[string]Header{
+						"ETag":             etagHeaders["ETag"],
+						"X-Schema-Version": {Description: "Version of the plan schema the plan was validated against", Schema: Schema{"type": "string"}},
+					},
+					Content: jsonContent(ref("Plan")),
+				},
+				"304": {Description: "The cached copy is current"},
+				"404": responseRef("NotFound"),
+			},
+		},
+		"PUT /api/v1/plans": withIdempotency(&Operation{
+			OperationID: "updatePlan",
+			Summary:     "Replace a plan",
+			Description: "Replaces the plan with the body's objectId.",
+			Tags:        []string{"Plans"},
+			RequestBody: body(ref("Plan")),
+			Responses: map[string]*Response{
+				"200": jsonResponse("Plan replaced", messageSchema()),
+				"400": responseRef("BadRequest"),
+				"403": responseRef("Forbidden"),
+				"404": responseRef("NotFound"),
+			},
+		}),
+		"PATCH /api/v1/plans": withIdempotency(&Operation{
+			OperationID: "patchPlan",
+			Summary:     "Merge fields into a plan",
+			Description: "Linked plan services are merged by objectId.",
+			Tags:        []string{"Plans"},
+			Parameters:  []Parameter{planID, ifMatch},
+			RequestBody: body(ref("PlanPatch")),
+			Responses: map[string]*Response{
+				"200": jsonResponse("Plan updated", messageSchema("planId")),
+				"400": responseRef("BadRequest"),
+				"403": responseRef("Forbidden"),
+				"404": responseRef("NotFound"),
+				"412": responseRef("PreconditionFailed"),
+			},
+		}),
+		"DELETE /api/v1/plans": withIdempotency(&Operation{
+			OperationID: "deletePlan",
+			Summary:     "Delete a plan",
+			Tags:        []string{"Plans"},
+			Parameters:  []Parameter{planID},
+			Responses: map[string]*Response{
+				"204": noContent,
+				"403": responseRef("Forbidden"),
+				"404": responseRef("NotFound"),
+			},
+		}),
+		"GET /api/v1/plans/changes": {
+			OperationID: "watchPlanChanges",
+			Summary:     "Stream plan changes",
+			Description: "Streams change events as Server-Sent Events, or as one JSON message per event when the request upgrades to a WebSocket. Only changes of orgs the caller can view are sent. When events the subscriber has not received yet are trimmed, Server-Sent Events end with a `reset` event carrying an events_expired problem, and WebSockets close with code 4410.",
+			Tags:        []string{"Plans"},
+			Parameters: []Parameter{
+				query("org", "Only changes of this org", false),
+				{Name: "include", In: "query", Description: "`document` adds the plan after the change to each event", Schema: Schema{"type": "string", "enum": []string{"document"}}},
+				query("lastEventId", "Resume after this event ID, like the Last-Event-ID header", false),
+				{Name: "Last-Event-ID", In: "header", Description: "Resume after this event ID; without one the stream starts with new changes", Schema: Schema{"type": "string"}},
+			},
+			Responses: map[string]*Response{
+				"101": {Description: "Switched to a WebSocket sending ChangeEvent messages"},
+				"200": {Description: "Server-Sent Events; each event's data is a ChangeEvent", Content: map[string]MediaType{"text/event-stream": {Schema: ref("ChangeEvent")}}},
+				"400": responseRef("BadRequest"),
+				"403": responseRef("Forbidden"),
+				"410": responseRef("EventsExpired"),
+				"503": responseRef("TooManySubscribers"),
+			},
+		},
+		"POST /api/v1/search": {
+			OperationID: "searchPlans",
+			Summary:     "Search plans",
+			Description: "Runs a match query on a field, limited to the orgs the caller can view.",
+			Tags:        []string{"Plans"},
+			RequestBody: body(ref("SearchRequest")),
+			Responses: map[string]*Response{
+				"200": jsonResponse("The search response", ref("SearchResult")),
+				"400": responseRef("BadRequest"),
+				"503": responseRef("ServiceUnavailable"),
+				"504": responseRef("ServiceUnavailable"),
+			},
+		},
+
+		// Webhooks
+		"GET /api/v1/webhooks": {
+			OperationID: "listWebhooks",
+			Summary:     "List the webhooks of orgs the caller administers",
+			Tags:        []string{"Webhooks"},
+			Responses:   map[string]*Response{"200": jsonResponse("Webhooks, without secrets", arrayOf(ref("Webhook")))},
+		},
+		"POST /api/v1/webhooks": {
+			OperationID: "createWebhook",
+			Summary:     "Subscribe an endpoint to plan events of an org",
+			Description: "Requires the admin role in the org. The signing secret is generated unless provided, and only returned here.",
+			Tags:        []string{"Webhooks"},
+			RequestBody: body(Schema{
+				"type": "object",
+				"properties": Schema{
+					"url":    Schema{"type": "string", "format": "uri"},
+					"org":    Schema{"type": "string"},
+					"events": arrayOf(Schema{"type": "string", "enum": webhooks.EventTypes}),
+					"secret": Schema{"type": "string"},
+				},
+				"required": []string{"url", "org"},
+			}),
+			Responses: map[string]*Response{
+				"201": jsonResponse("Webhook created, with its secret", ref("Webhook")),
+				"400": responseRef("BadRequest"),
+				"403": responseRef("Forbidden"),
+			},
+		},
+		"GET /api/v1/webhooks/:id": {
+			OperationID: "getWebhook",
+			Summary:     "Fetch a webhook",
+			Tags:        []string{"Webhooks"},
+			Responses: map[string]*Response{
+				"200": jsonResponse("The webhook, without its secret", ref("Webhook")),
+				"404": responseRef("NotFound"),
+			},
+		},
+		"DELETE /api/v1/webhooks/:id": {
+			OperationID: "deleteWebhook",
+			Summary:     "Delete a webhook",
+			Tags:        []string{"Webhooks"},
+			Responses:   map[string]*Response{"204": noContent, "404": responseRef("NotFound")},
+		},
+		"POST /api/v1/webhooks/:id/enable": {
+			OperationID: "enableWebhook",
+			Summary:     "Reactivate a webhook disabled after repeated failures",
+			Tags:        []string{"Webhooks"},
+			Responses: map[string]*Response{
+				"200": jsonResponse("The webhook", ref("Webhook")),
+				"404": responseRef("NotFound"),
+			},
+		},
+		"GET /api/v1/webhooks/:id/deliveries": {
+			OperationID: "listWebhookDeliveries",
+			Summary:     "List the latest delivery attempts of a webhook",
+			Tags:        []string{"Webhooks"},
+			Responses: map[string]*Response{
+				"200": jsonResponse("Up to 100 attempts, newest first", arrayOf(ref("WebhookDelivery"))),
+				"404": responseRef("NotFound"),
+			},
+		},
+
+		// GraphQL
+		"POST /graphql": {
+			OperationID: "graphql",
+			Summary:     "Run a GraphQL query or mutation",
+			Description: "Queries are bounded by GRAPHQL_MAX_DEPTH and GRAPHQL_MAX_COMPLEXITY. Errors are reported in the response's `errors`, with the problem code in their `extensions`.",
+			Tags:        []string{"GraphQL"},
+			RequestBody: body(Schema{
+				"type": "object",
+				"properties": Schema{
+					"query":         Schema{"type": "string"},
+					"operationName": Schema{"type": "string"},
+					"variables":     Schema{"type": "object", "additionalProperties": true},
+				},
+				"required": []string{"query"},
+			}),
+			Responses: map[string]*Response{
+				"200": jsonResponse("The GraphQL result", Schema{
+					"type": "object",
+					"properties": Schema{
+						"data":   Schema{},
+						"errors": arrayOf(Schema{"type": "object", "additionalProperties": true}),
+					},
+				}),
+				"400": responseRef("BadRequest"),
+			},
+		},
+
+		// Admin: schemas
+		"GET /api/v1/admin/schemas": {
+			OperationID: "listSchemas",
+			Summary:     "List schema names with their active versions",
+			Tags:        []string{"Admin"},
+			Responses: map[string]*Response{"200": jsonResponse("Schemas", arrayOf(Schema{
+				"type":       "object",
+				"properties": Schema{"name": Schema{"type": "string"}, "activeVersion": Schema{"type": "string"}},
+			}))},
+		},
+		"GET /api/v1/admin/schemas/:name": {
+			OperationID: "listSchemaVersions",
+			Summary:     "List the versions of a schema",
+			Tags:        []string{"Admin"},
+			Responses: map[string]*Response{
+				"200": jsonResponse("Versions", Schema{
+					"type":       "object",
+					"properties": Schema{"name": Schema{"type": "string"}, "versions": arrayOf(ref("SchemaVersion"))},
+				}),
+				"404": responseRef("NotFound"),
+			},
+		},
+		"POST /api/v1/admin/schemas/:name": {
+			OperationID: "uploadSchema",
+			Summary:     "Upload a new, inactive schema version",
+			Tags:        []string{"Admin"},
+			RequestBody: &RequestBody{Required: true, Content: map[string]MediaType{"application/schema+json": {Schema: Schema{"type": "object"}}}},
+			Responses: map[string]*Response{
+				"201": jsonResponse("Version uploaded", messageSchema("name", "version")),
+				"400": responseRef("BadRequest"),
+				"404": responseRef("NotFound"),
+			},
+		},
+		"GET /api/v1/admin/schemas/:name/versions/:version": {
+			OperationID: "getSchemaVersion",
+			Summary:     "Fetch a schema version",
+			Tags:        []string{"Admin"},
+			Responses: map[string]*Response{
+				"200": {Description: "The JSON Schema", Content: map[string]MediaType{"application/schema+json": {Schema: Schema{"type": "object"}}}},
+				"404": responseRef("NotFound"),
+			},
+		},
+		"PUT /api/v1/admin/schemas/:name/active": {
+			OperationID: "activateSchema",
+			Summary:     "Activate a schema version",
+			Tags:        []string{"Admin"},
+			RequestBody: body(Schema{"type": "object", "properties": Schema{"version": Schema{"type": "string"}}, "required": []string{"version"}}),
+			Responses: map[string]*Response{
+				"200": jsonResponse("Version activated", messageSchema("name", "version")),
+				"400": responseRef("BadRequest"),
+				"404": responseRef("NotFound"),
+			},
+		},
+
+		// Admin: machine credentials
+		"GET /api/v1/admin/apikeys": {
+			OperationID: "listAPIKeys",
+			Summary:     "List API keys, without secrets",
+			Tags:        []string{"Admin"},
+			Responses:   map[string]*Response{"200": jsonResponse("API keys", arrayOf(ref("APIKey")))},
+		},
+		"POST /api/v1/admin/apikeys": {
+			OperationID: "createAPIKey",
+			Summary:     "Issue an API key",
+			Description: "The key is only returned in this response.",
+			Tags:        []string{"Admin"},
+			RequestBody: body(credentialRequest),
+			Responses: map[string]*Response{
+				"201": jsonResponse("API key issued", Schema{"type": "object", "properties": Schema{"apiKey": ref("APIKey"), "key": Schema{"type": "string"}}}),
+				"400": responseRef("BadRequest"),
+			},
+		},
+		"DELETE /api/v1/admin/apikeys/:id": {
+			OperationID: "revokeAPIKey",
+			Summary:     "Revoke an API key",
+			Tags:        []string{"Admin"},
+			Responses:   map[string]*Response{"204": noContent, "404": responseRef("NotFound")},
+		},
+		"POST /api/v1/admin/hmackeys": {
+			OperationID: "createHMACKey",
+			Summary:     "Issue an HMAC signing key",
+			Description: "The secret is only returned in this response.",
+			Tags:        []string{"Admin"},
+			RequestBody: body(credentialRequest),
+			Responses: map[string]*Response{
+				"201": jsonResponse("HMAC key issued", Schema{"type": "object", "properties": Schema{"hmacKey": ref("HMACKey"), "secret": Schema{"type": "string"}}}),
+				"400": responseRef("BadRequest"),
+			},
+		},
+		"DELETE /api/v1/admin/hmackeys/:id": {
+			OperationID: "revokeHMACKey",
+			Summary:     "Revoke an HMAC key",
+			Tags:        []string{"Admin"},
+			Responses:   map[string]*Response{"204": noContent, "404": responseRef("NotFound")},
+		},
+
+		// Admin: memberships
+		"GET /api/v1/admin/members/:subject": {
+			OperationID: "getMemberships",
+			Summary:     "List the stored org memberships of a caller ID (<method>:<subject>)",
+			Tags:        []string{"Admin"},
+			Responses: map[string]*Response{"200": jsonResponse("Memberships", Schema{
+				"type": "object",
+				"properties": Schema{
+					"subject":     Schema{"type": "string"},
+					"memberships": Schema{"type": "object", "additionalProperties": role},
+				},
+			})},
+		},
+		"PUT /api/v1/admin/members/:subject/orgs/:org": {
+			OperationID: "grantMembership",
+			Summary:     "Grant a caller ID (<method>:<subject>) a role in an org",
+			Tags:        []string{"Admin"},
+			RequestBody: body(Schema{"type": "object", "properties": Schema{"role": role}, "required": []string{"role"}}),
+			Responses: map[string]*Response{
+				"200": jsonResponse("Membership granted", Schema{
+					"type":       "object",
+					"properties": Schema{"subject": Schema{"type": "string"}, "org": Schema{"type": "string"}, "role": role},
+				}),
+				"400": responseRef("BadRequest"),
+			},
+		},
+		"DELETE /api/v1/admin/members/:subject/orgs/:org": {
+			OperationID: "revokeMembership",
+			Summary:     "Revoke a membership",
+			Tags:        []string{"Admin"},
+			Responses:   map[string]*Response{"204": noContent, "404": responseRef("NotFound")},
+		},
+
+		// Admin: audit log
+		"GET /api/v1/admin/audit": {
+			OperationID: "queryAuditLog",
+			Summary:     "Read a page of audit entries, oldest first",
+			Tags:        []string{"Admin"},
+			Parameters: append(auditFilters,
+				query("after", "Continue after this entry ID (the previous page's next)", false),
+				Parameter{Name: "limit", In: "query", Schema: Schema{"type": "integer", "minimum": 1, "maximum": 1000, "default": 100}},
+			),
+			Responses: map[string]*Response{
+				"200": jsonResponse("A page of entries; next is set when more may follow", Schema{
+					"type":       "object",
+					"properties": Schema{"entries": arrayOf(ref("AuditEntry")), "next": Schema{"type": "string"}},
+				}),
+				"400": responseRef("BadRequest"),
+			},
+		},
+		"GET /api/v1/admin/audit/export": {
+			OperationID: "exportAuditLog",
+			Summary:     "Download every matching audit entry",
+			Tags:        []string{"Admin"},
+			Parameters:  auditFilters,
+			Responses: map[string]*Response{
+				"200": {Description: "One AuditEntry per line", Content: map[string]MediaType{"application/x-ndjson": {Schema: ref("AuditEntry")}}},
+				"400": responseRef("BadRequest"),
+			},
+		},
+
+		"GET /api/v1/admin/status": {
+			OperationID: "getStatus",
+			Summary:     "Detailed state of every dependency",
+			Description: "Runs the readiness checks and returns their errors, latencies and details.",
+			Tags:        []string{"Admin", "Health"},
+			Responses: map[string]*Response{
+				"200": jsonResponse("The API is ready, possibly degraded", ref("HealthReport")),
+				"503": jsonResponse("A critical dependency is failing", ref("HealthReport")),
+			},
+		},
+
+		// Health
+		"GET /healthz": {
+			OperationID: "getLiveness",
+			Summary:     "Liveness probe",
+			Description: "Answers while the process serves requests; no dependency is checked.",
+			Tags:        []string{"Health"},
+			Security:    &public,
+			Responses:   map[string]*Response{"200": jsonResponse("Alive", Schema{"type": "object", "properties": Schema{"status": Schema{"type": "string"}}})},
+		},
+		"GET /readyz": {
+			OperationID: "getReadiness",
+			Summary:     "Readiness probe",
+			Description: "Checks Redis, the plan schemas, Elasticsearch and the RabbitMQ connection. Only critical checks (Redis, schemas) make the API unavailable; the others report it as degraded. Fails while the API shuts down.",
+			Tags:        []string{"Health"},
+			Security:    &public,
+			Responses: map[string]*Response{
+				"200": jsonResponse("Ready, possibly degraded", ref("HealthReport")),
+				"503": jsonResponse("Not ready", ref("HealthReport")),
+			},
+		},
+		"GET /metrics": {
+			OperationID: "getMetrics",
+			Summary:     "Prometheus metrics",
+			Description: "Request durations by route and status, Redis operation latency, schema validation failures by keyword and published events, in the Prometheus text exposition format.",
+			Tags:        []string{"Health"},
+			Security:    &public,
+			Responses:   map[string]*Response{"200": {Description: "Metrics", Content: map[string]MediaType{"text/plain": {}}}},
+		},
+
+		// Documentation
+		"GET /api/v1/openapi.json": {
+			OperationID: "getOpenAPIDocument",
+			Summary:     "This OpenAPI document",
+			Tags:        []string{"Documentation"},
+			Security:    &public,
+			Responses:   map[string]*Response{"200": jsonResponse("The OpenAPI document", Schema{"type": "object"})},
+		},
+		"GET /api/v1/docs": {
+			OperationID: "getAPIDocs",
+			Summary:     "API reference rendered from this document",
+			Tags:        []string{"Documentation"},
+			Security:    &public,
+			Responses:   map[string]*Response{"200": {Description: "HTML page", Content: map[string]MediaType{"text/html": {}}}},
+		},
+	}
+}
+
+var (
+	credentialRequest = Schema{
+		"type": "object",
+		"properties": Schema{
+			"subject": Schema{"type": "string"},
+			"scopes":  arrayOf(Schema{"type": "string"}),
+		},
+		"required": []string{"subject"},
+	}
+	role = Schema{"type": "string", "enum": []string{"viewer", "editor", "admin"}}
+)
+
+// resourceOperations documents the CRUD routes every schema-registered resource gets.
+func resourceOperations(resource *resources.Resource) map[string]*Operation {
+	path := "/api/v1/" + resource.Path
+	schemaName := resourceSchemaName(resource)
+	id := query("id", "objectId of the "+resource.Name, true)
+	tags := []string{"Resources"}
+	written := func(description string, status int) map[string]*Response {
+		return map[string]*Response{
+			strconv.Itoa(status): {Description: description, Headers: etagHeaders, Content: jsonContent(messageSchema("objectId"))},
+			"400":                responseRef("BadRequest"),
+			"403":                responseRef("Forbidden"),
+		}
+	}
+
+	create := withIdempotency(&Operation{
+		OperationID: "create" + schemaName,
+		Summary:     fmt.Sprintf("Create a %s", resource.Name),
+		Tags:        tags,
+		RequestBody: body(ref(schemaName)),
+		Responses:   written(resource.Name+" created", http.StatusCreated),
+	})
+	update := withIdempotency(&Operation{
+		OperationID: "update" + schemaName,
+		Summary:     fmt.Sprintf("Replace a %s", resource.Name),
+		Tags:        tags,
+		Parameters:  []Parameter{ifMatch},
+		RequestBody: body(ref(schemaName)),
+		Responses:   written(resource.Name+" replaced", http.StatusOK),
+	})
+	update.Responses["404"] = responseRef("NotFound")
+	update.Responses["412"] = responseRef("PreconditionFailed")
+	patch := withIdempotency(&Operation{
+		OperationID: "patch" + schemaName,
+		Summary:     fmt.Sprintf("Merge fields into a %s", resource.Name),
+		Tags:        tags,
+		Parameters:  []Parameter{id, ifMatch},
+		RequestBody: body(ref(schemaName + "Patch")),
+		Responses:   written(resource.Name+" updated", http.StatusOK),
+	})
+	patch.Responses["404"] = responseRef("NotFound")
+	patch.Responses["412"] = responseRef("PreconditionFailed")
+
+	return map[string]*Operation{
+		"POST " + path: create,
+		"GET " + path: {
+			OperationID: "get" + schemaName,
+			Summary:     fmt.Sprintf("Fetch a %s", resource.Name),
+			Tags:        tags,
+			Parameters:  []Parameter{id, ifNoneMatch},
+			Responses: map[string]*Response{
+				"200": {Description: "The " + resource.Name, Headers: etagHeaders, Content: jsonContent(ref(schemaName))},
+				"304": {Description: "The cached copy is current"},
+				"404": responseRef("NotFound"),
+			},
+		},
+		"PUT " + path:   update,
+		"PATCH " + path: patch,
+		"DELETE " + path: withIdempotency(&Operation{
+			OperationID: "delete" + schemaName,
+			Summary:     fmt.Sprintf("Delete a %s", resource.Name),
+			Tags:        tags,
+			Parameters:  []Parameter{id, ifMatch},
+			Responses: map[string]*Response{
+				"204": noContent,
+				"403": responseRef("Forbidden"),
+				"404": responseRef("NotFound"),
+				"412": responseRef("PreconditionFailed"),
+			},
+		}),
+	}
+}
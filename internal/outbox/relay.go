@@ -0,0 +1,197 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"BigDataForge/internal/changefeed"
+	"BigDataForge/internal/events"
+	"BigDataForge/internal/metrics"
+	"BigDataForge/internal/rabbitmq"
+	"BigDataForge/internal/tracing"
+
+	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The plan change stream doubles as a transactional outbox: every plan write
+// appends its event in the same Redis transaction, and the relay publishes
+// those events to RabbitMQ with publisher confirms. An event is acknowledged
+// in the stream only after the broker confirmed it, so a crash or broker
+// outage delays events but never loses them (delivery is at least once). The
+// relay also trims the stream, which keeps events it has not acknowledged.
+
+const (
+	group          = "outbox"
+	batchSize      = 100
+	readBlock      = 5 * time.Second
+	reconnectDelay = 5 * time.Second
+	confirmTimeout = 30 * time.Second
+)
+
+// Relay publishes plan change events from the stream to the plan exchange,
+// over the connection of a supervisor.
+type Relay struct {
+	feed       *changefeed.Feed
+	supervisor *rabbitmq.Supervisor
+	exchange   string
+	consumer   string
+
+	// lastPublished is a Unix time in milliseconds
+	lastPublished atomic.Int64
+}
+
+func NewRelay(feed *changefeed.Feed, supervisor *rabbitmq.Supervisor, exchange string) *Relay {
+	consumer, err := os.Hostname()
+	if err != nil || consumer == "" {
+		consumer = "api"
+	}
+	return &Relay{feed: feed, supervisor: supervisor, exchange: exchange, consumer: consumer}
+}
+
+// Run relays events until ctx is cancelled, opening a new channel after
+// failures. A batch being published when ctx is cancelled is still confirmed.
+func (r *Relay) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := r.relay(ctx, false); err != nil && ctx.Err() == nil {
+			slog.Error("Outbox relay stopped", "error", err, "retryIn", reconnectDelay.String())
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// Flush publishes the events left in the stream and returns once it is empty.
+// It is meant for shutdown, after Run returned and writes stopped.
+func (r *Relay) Flush(ctx context.Context) error {
+	return r.relay(ctx, true)
+}
+
+// Helper to relay over one channel; flush reads without blocking and returns
+// when no events are left
+func (r *Relay) relay(ctx context.Context, flush bool) error {
+	if err := r.feed.EnsureGroup(ctx, group); err != nil {
+		return err
+	}
+
+	ch, err := r.supervisor.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	if err := ch.Confirm(false); err != nil {
+		return err
+	}
+	confirms := ch.NotifyPublish(make(chan amqp.Confirmation, batchSize))
+
+	// Events read before a restart but never confirmed go out first
+	pending := true
+	block := readBlock
+	if flush {
+		block = -1
+	}
+	for ctx.Err() == nil {
+		events, err := r.feed.ReadGroup(ctx, group, r.consumer, pending, batchSize, block)
+		if err != nil {
+			return err
+		}
+		if pending && len(events) == 0 {
+			pending = false
+			continue
+		}
+		if len(events) == 0 {
+			if flush {
+				return nil
+			}
+			continue
+		}
+
+		if err := r.publish(ch, confirms, events); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Helper to publish a batch and acknowledge it in the stream once confirmed
+func (r *Relay) publish(ch *amqp.Channel, confirms <-chan amqp.Confirmation, events []changefeed.Event) error {
+	for _, event := range events {
+		if err := r.publishEvent(ch, event); err != nil {
+			return err
+		}
+	}
+
+	ids := make([]string, 0, len(events))
+	timeout := time.After(confirmTimeout)
+	for _, event := range events {
+		select {
+		case confirm, ok := <-confirms:
+			if !ok {
+				return errors.New("channel closed before publish was confirmed")
+			}
+			if !confirm.Ack {
+				return errors.New("broker rejected event " + event.ID)
+			}
+			ids = append(ids, event.ID)
+			metrics.MessagesPublished.WithLabelValues(r.exchange).Inc()
+		case <-timeout:
+			return errors.New("timed out waiting for publish confirms")
+		}
+	}
+	if err := r.feed.Ack(context.Background(), group, ids...); err != nil {
+		return err
+	}
+	r.lastPublished.Store(time.Now().UnixMilli())
+
+	if err := r.feed.Trim(context.Background(), group, ids[len(ids)-1]); err != nil {
+		slog.Warn("Failed to trim the change stream", "error", err)
+	}
+	return nil
+}
+
+// Helper to publish one event in a producer span that continues the trace of
+// the write, and to pass the span on in the message headers
+func (r *Relay) publishEvent(ch *amqp.Channel, event changefeed.Event) error {
+	ctx := tracing.Extract(context.Background(), event.Trace)
+	routingKey := rabbitmq.RoutingKey(event)
+	ctx, span := tracing.Tracer().Start(ctx, r.exchange+" publish", trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination.name", r.exchange),
+			attribute.String("messaging.rabbitmq.destination.routing_key", routingKey),
+			attribute.String("messaging.message.id", event.ID),
+		))
+	defer span.End()
+
+	publishing, err := events.Publishing(event)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	tracing.InjectHeaders(ctx, publishing.Headers)
+	if err := ch.Publish(r.exchange, routingKey, false, false, publishing); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
+
+// Check is a health check of the relay's broker connection.
+func (r *Relay) Check(ctx context.Context) (map[string]interface{}, error) {
+	details, err := r.supervisor.Check(ctx)
+	if published := r.lastPublished.Load(); published != 0 {
+		details["lastPublishedAt"] = time.UnixMilli(published)
+	}
+	return details, err
+}
@@ -0,0 +1,122 @@
+package problems
+
+import (
+	"errors"
+	"net/http"
+
+	"BigDataForge/internal/requestid"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType is the media type of RFC 7807 problem documents.
+const ContentType = "application/problem+json"
+
+// Stable error codes. Clients should branch on these rather than on the detail text.
+const (
+	CodeInvalidRequest     = "invalid_request"
+	CodeValidationFailed   = "validation_failed"
+	CodeRuleViolation      = "business_rule_violation"
+	CodeUnauthorized       = "unauthorized"
+	CodeForbidden          = "forbidden"
+	CodeNotFound           = "not_found"
+	CodeConflict           = "conflict"
+	CodePreconditionFailed = "precondition_failed"
+	CodeRateLimited        = "rate_limited"
+	CodeIdempotencyReused  = "idempotency_key_reused"
+	CodeEventsExpired      = "events_expired"
+	CodeUnavailable        = "unavailable"
+	CodeInternal           = "internal_error"
+)
+
+// Codes lists every stable error code, for the OpenAPI document.
+var Codes = []string{
+	CodeInvalidRequest, CodeValidationFailed, CodeRuleViolation, CodeUnauthorized, CodeForbidden, CodeNotFound,
+	CodeConflict, CodePreconditionFailed, CodeRateLimited, CodeIdempotencyReused, CodeEventsExpired, CodeUnavailable,
+	CodeInternal,
+}
+
+// FieldError locates one validation failure in the request document.
+type FieldError struct {
+	Pointer string `json:"pointer"`
+	Keyword string `json:"keyword"`
+	Message string `json:"message"`
+}
+
+// Problem is an RFC 7807 problem details document extended with a stable
+// code, the request ID and, for validation failures, the field errors.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"requestId,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// TypeURI returns the problem type URI for a code.
+func TypeURI(code string) string {
+	return "urn:bigdataforge:problem:" + code
+}
+
+// New builds a problem for the current request.
+func New(c *gin.Context, status int, code, detail string) Problem {
+	return Problem{
+		Type:      TypeURI(code),
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  c.Request.URL.Path,
+		Code:      code,
+		RequestID: requestid.Get(c),
+	}
+}
+
+// Write aborts the request with the problem document.
+func Write(c *gin.Context, problem Problem) {
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
+
+// Abort writes a problem without field errors.
+func Abort(c *gin.Context, status int, code, detail string) {
+	Write(c, New(c, status, code, detail))
+}
+
+// AbortWithErrors writes a problem listing field errors.
+func AbortWithErrors(c *gin.Context, status int, code, detail string, fieldErrors []FieldError) {
+	problem := New(c, status, code, detail)
+	problem.Errors = fieldErrors
+	Write(c, problem)
+}
+
+// Error is a problem raised outside of a gin handler, by code shared with the
+// gRPC server. Handlers render it with AbortWithError.
+type Error struct {
+	Status int
+	Code   string
+	Detail string
+	Errors []FieldError
+}
+
+// NewError builds a problem error without field errors.
+func NewError(status int, code, detail string) *Error {
+	return &Error{Status: status, Code: code, Detail: detail}
+}
+
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+// AbortWithError writes err as a problem. Errors other than *Error are
+// reported as internal errors without exposing their text.
+func AbortWithError(c *gin.Context, err error) {
+	var problemErr *Error
+	if !errors.As(err, &problemErr) {
+		Abort(c, http.StatusInternalServerError, CodeInternal, "Internal server error")
+		return
+	}
+	AbortWithErrors(c, problemErr.Status, problemErr.Code, problemErr.Detail, problemErr.Errors)
+}
@@ -0,0 +1,18 @@
+package rabbitmq
+
+import (
+	"strings"
+
+	"BigDataForge/internal/changefeed"
+)
+
+// Helper to escape the org as one word of a routing key
+var orgEscaper = strings.NewReplacer("%", "%25", ".", "%2E")
+
+// RoutingKey returns the routing key of an event, plan.<org>.<type>. Dots in
+// the org, as in domain names, are escaped as %2E (and percent signs as %25),
+// so that it is a single word: "plan.example%2Ecom.*" binds the events of
+// example.com, and "plan.*.delete" the deletes of every org.
+func RoutingKey(event changefeed.Event) string {
+	return "plan." + orgEscaper.Replace(event.Org) + "." + event.Type
+}
@@ -0,0 +1,232 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+// Connection states
+const (
+	StateConnecting = "connecting"
+	StateConnected  = "connected"
+	StateStopped    = "stopped"
+)
+
+const (
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+// ErrNotConnected is returned for channels requested while the supervisor has
+// no connection.
+var ErrNotConnected = errors.New("not connected to RabbitMQ")
+
+// Consumer consumes a queue on its own channel. Process handles deliveries
+// until the channel is closed: after the consumer was canceled on shutdown, or
+// when the connection was lost, in which case the consumer is registered again
+// once the supervisor reconnected.
+type Consumer struct {
+	Queue    string
+	Tag      string
+	Prefetch int
+	Process  func(deliveries <-chan amqp.Delivery)
+}
+
+// Supervisor keeps one connection to the broker, shared by publishers and
+// consumers. When the connection is lost it reconnects with an exponential
+// backoff, declares the topology again and restarts its consumers.
+// Publishers open channels with Channel for as long as they need them.
+type Supervisor struct {
+	url       string
+	topology  Topology
+	consumers []Consumer
+
+	mu       sync.Mutex
+	conn     *amqp.Connection
+	state    string
+	since    time.Time
+	lastErr  error
+	connects int
+}
+
+func NewSupervisor(url string, topology Topology) *Supervisor {
+	return &Supervisor{url: url, topology: topology, state: StateConnecting, since: time.Now()}
+}
+
+// Consume adds a consumer started on every connection. Add consumers before
+// calling Run.
+func (s *Supervisor) Consume(consumer Consumer) {
+	s.consumers = append(s.consumers, consumer)
+}
+
+// Run connects and stays connected until ctx is cancelled. Stopping cancels
+// the consumers, waits for them to process the deliveries they received, and
+// closes the connection.
+func (s *Supervisor) Run(ctx context.Context) {
+	delay := minReconnectDelay
+	for {
+		conn, err := s.connect()
+		if err == nil {
+			delay = minReconnectDelay
+			err = s.serve(ctx, conn)
+		}
+		if ctx.Err() != nil {
+			s.setState(StateStopped, nil, nil)
+			return
+		}
+		s.setState(StateConnecting, nil, err)
+		slog.Error("RabbitMQ connection failed", "error", err, "retryIn", delay.String())
+
+		select {
+		case <-ctx.Done():
+			s.setState(StateStopped, nil, nil)
+			return
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
+
+// Helper to dial the broker and declare the topology
+func (s *Supervisor) connect() (*amqp.Connection, error) {
+	conn, err := amqp.Dial(s.url)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := conn.Channel()
+	if err == nil {
+		err = s.topology.Declare(ch)
+		ch.Close()
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	s.setState(StateConnected, conn, nil)
+	slog.Info("Connected to RabbitMQ")
+	return conn, nil
+}
+
+// Helper to run the consumers over a connection until it is lost, or until
+// ctx is cancelled. A consumer channel closed by the broker, for example
+// because its queue was deleted, drops the whole connection, so that the
+// topology is declared again.
+func (s *Supervisor) serve(ctx context.Context, conn *amqp.Connection) error {
+	closed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	channelClosed := make(chan *amqp.Error, len(s.consumers))
+	var processing sync.WaitGroup
+	channels := make([]*amqp.Channel, 0, len(s.consumers))
+	for _, consumer := range s.consumers {
+		ch, err := s.startConsumer(conn, consumer, &processing)
+		if err != nil {
+			conn.Close()
+			processing.Wait()
+			return err
+		}
+		channels = append(channels, ch)
+		go func(notify chan *amqp.Error) {
+			if err := <-notify; err != nil {
+				channelClosed <- err
+			}
+		}(ch.NotifyClose(make(chan *amqp.Error, 1)))
+	}
+
+	select {
+	case err := <-closed:
+		// Deliveries channels are closed with the connection
+		processing.Wait()
+		if err == nil {
+			return errors.New("connection closed")
+		}
+		return err
+	case err := <-channelClosed:
+		conn.Close()
+		processing.Wait()
+		return err
+	case <-ctx.Done():
+		for i, ch := range channels {
+			if err := ch.Cancel(s.consumers[i].Tag, false); err != nil {
+				slog.Error("Failed to cancel consumer", "consumer", s.consumers[i].Tag, "error", err)
+			}
+		}
+		processing.Wait()
+		return conn.Close()
+	}
+}
+
+// Helper to register a consumer on its own channel and process its deliveries
+// in the background
+func (s *Supervisor) startConsumer(conn *amqp.Connection, consumer Consumer, processing *sync.WaitGroup) (*amqp.Channel, error) {
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+	if consumer.Prefetch > 0 {
+		if err := ch.Qos(consumer.Prefetch, 0, false); err != nil {
+			return nil, err
+		}
+	}
+	deliveries, err := ch.Consume(consumer.Queue, consumer.Tag, false, false, false, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	processing.Add(1)
+	go func() {
+		defer processing.Done()
+		consumer.Process(deliveries)
+	}()
+	return ch, nil
+}
+
+// Channel opens a channel on the current connection. Callers close it when
+// done, and open a new one after the connection was lost.
+func (s *Supervisor) Channel() (*amqp.Channel, error) {
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+	if conn == nil {
+		return nil, ErrNotConnected
+	}
+	return conn.Channel()
+}
+
+// Helper to record a state change; conn is set while connected
+func (s *Supervisor) setState(state string, conn *amqp.Connection, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if state == StateConnected {
+		s.connects++
+	}
+	if state != s.state {
+		s.since = time.Now()
+	}
+	s.state, s.conn = state, conn
+	if err != nil {
+		s.lastErr = err
+	}
+}
+
+// Check is a health check of the connection. It reports the state, since
+// when the supervisor is in it, the reconnections and the last error.
+func (s *Supervisor) Check(ctx context.Context) (map[string]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	details := map[string]interface{}{"state": s.state, "since": s.since}
+	if s.connects > 1 {
+		details["reconnects"] = s.connects - 1
+	}
+	if s.lastErr != nil {
+		details["lastError"] = s.lastErr.Error()
+	}
+	if s.conn == nil {
+		return details, ErrNotConnected
+	}
+	return details, nil
+}
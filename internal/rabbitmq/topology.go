@@ -0,0 +1,57 @@
+package rabbitmq
+
+import (
+	"github.com/streadway/amqp"
+)
+
+// Topology lists the exchanges, queues and bindings the binaries rely on. The
+// supervisor declares it on every connection, so a broker that restarted
+// without them gets them back before anything is published or consumed.
+// Declarations are idempotent, but their arguments must match those of
+// existing entities.
+type Topology struct {
+	Exchanges []Exchange
+	Queues    []Queue
+	Bindings  []Binding
+}
+
+type Exchange struct {
+	Name    string
+	Kind    string
+	Durable bool
+	Args    amqp.Table
+}
+
+type Queue struct {
+	Name       string
+	Durable    bool
+	AutoDelete bool
+	Args       amqp.Table
+}
+
+type Binding struct {
+	Queue    string
+	Exchange string
+	Key      string
+	Args     amqp.Table
+}
+
+// Declare declares exchanges, then queues, then the bindings between them.
+func (t Topology) Declare(ch *amqp.Channel) error {
+	for _, exchange := range t.Exchanges {
+		if err := ch.ExchangeDeclare(exchange.Name, exchange.Kind, exchange.Durable, false, false, false, exchange.Args); err != nil {
+			return err
+		}
+	}
+	for _, queue := range t.Queues {
+		if _, err := ch.QueueDeclare(queue.Name, queue.Durable, queue.AutoDelete, false, false, queue.Args); err != nil {
+			return err
+		}
+	}
+	for _, binding := range t.Bindings {
+		if err := ch.QueueBind(binding.Queue, binding.Key, binding.Exchange, false, binding.Args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"BigDataForge/internal/auth"
+	"BigDataForge/internal/config"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Limits are token buckets kept in Redis so every API replica shares them.
+// A bucket holds up to Limit tokens and refills continuously at Limit per
+// Period; each request takes one token. Buckets live under
+// ratelimit:<policy>:<key> and expire once they would be full again.
+
+// Policy is a named limit of Limit requests per Period.
+type Policy struct {
+	Name   string
+	Limit  int
+	Period time.Duration
+}
+
+// Disabled reports whether the policy lets every request through.
+func (p Policy) Disabled() bool {
+	return p.Limit <= 0
+}
+
+var periods = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+}
+
+// ParsePolicy reads a limit such as "60/m" or "10000/d"; "off" disables the policy.
+func ParsePolicy(name, value string) (Policy, error) {
+	if value == "off" {
+		return Policy{Name: name}, nil
+	}
+	count, unit, ok := strings.Cut(value, "/")
+	limit, err := strconv.Atoi(count)
+	period, known := periods[unit]
+	if !ok || err != nil || limit <= 0 || !known {
+		return Policy{}, fmt.Errorf("invalid rate limit %q for %s, expected <count>/<s|m|h|d> or off", value, name)
+	}
+	return Policy{Name: name, Limit: limit, Period: period}, nil
+}
+
+// Policies are the limits applied by the API routes.
+type Policies struct {
+	IP      Policy // every request per client IP, before authentication
+	Default Policy // every authenticated request
+	Search  Policy // POST /search, in addition to Default
+	Write   Policy // create, update, patch and delete, in addition to Default
+}
+
+// NewPolicies parses the configured limits.
+func NewPolicies(limits config.RateLimits) (Policies, error) {
+	var policies Policies
+	for _, setting := range []struct {
+		policy *Policy
+		name   string
+		value  string
+	}{
+		{&policies.IP, "ip", limits.IP},
+		{&policies.Default, "default", limits.Default},
+		{&policies.Search, "search", limits.Search},
+		{&policies.Write, "write", limits.Write},
+	} {
+		policy, err := ParsePolicy(setting.name, setting.value)
+		if err != nil {
+			return Policies{}, err
+		}
+		*setting.policy = policy
+	}
+	return policies, nil
+}
+
+// Result describes the state of a bucket after a request.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next request is allowed, when denied
+}
+
+// Helper to describe a bucket holding tokens after a request; rate is in
+// tokens per millisecond
+func newResult(policy Policy, allowed bool, tokens, rate float64) Result {
+	msUntil := func(target float64) time.Duration {
+		return time.Duration(math.Ceil(math.Max(0, target-tokens)/rate)) * time.Millisecond
+	}
+	result := Result{
+		Allowed:   allowed,
+		Limit:     policy.Limit,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     msUntil(float64(policy.Limit)),
+	}
+	if !allowed {
+		result.RetryAfter = msUntil(1)
+	}
+	return result
+}
+
+// Headers returns the RateLimit-* headers of a result, and Retry-After when
+// the request was denied. Durations are rounded up to whole seconds, so that
+// clients do not retry too early.
+func (r Result) Headers(policy Policy) map[string]string {
+	headers := map[string]string{
+		"RateLimit-Limit":     strconv.Itoa(r.Limit),
+		"RateLimit-Remaining": strconv.Itoa(r.Remaining),
+		"RateLimit-Reset":     strconv.Itoa(ceilSeconds(r.Reset)),
+		"RateLimit-Policy":    fmt.Sprintf("%d;w=%d", policy.Limit, int(policy.Period.Seconds())),
+	}
+	if !r.Allowed {
+		headers["Retry-After"] = strconv.Itoa(ceilSeconds(r.RetryAfter))
+	}
+	return headers
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// The script refills the bucket for the time elapsed on the Redis clock, so
+// replicas with skewed clocks still agree, then tries to take one token.
+var takeToken = redis.NewScript(`
+redis.replicate_commands()
+local limit = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or limit
+local ts = tonumber(state[2]) or now
+tokens = math.min(limit, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((limit - tokens) / rate) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// CallerKey is the bucket key of a caller: the API key or signing key when
+// one was used, otherwise the authenticated subject, otherwise the client IP.
+func CallerKey(principal *auth.Principal, clientIP string) string {
+	if principal == nil {
+		return "ip:" + clientIP
+	}
+	if kid, ok := principal.Claims["kid"].(string); ok && (principal.Method == "apikey" || principal.Method == "hmac") {
+		return principal.Method + ":" + kid
+	}
+	return "sub:" + principal.ID()
+}
+
+// Limiter applies policies to keys.
+type Limiter struct {
+	redisClient *redis.Client
+}
+
+func NewLimiter(redisClient *redis.Client) *Limiter {
+	return &Limiter{redisClient: redisClient}
+}
+
+// Allow takes a token from the key's bucket for the policy.
+func (l *Limiter) Allow(ctx context.Context, policy Policy, key string) (Result, error) {
+	ratePerMs := float64(policy.Limit) / float64(policy.Period.Milliseconds())
+	values, err := takeToken.Run(ctx, l.redisClient, []string{"ratelimit:" + policy.Name + ":" + key}, policy.Limit, ratePerMs).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	if len(values) != 2 {
+		return Result{}, fmt.Errorf("unexpected rate limit script result %v", values)
+	}
+	allowed, _ := values[0].(int64)
+	remaining, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(remaining, 64)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return newResult(policy, allowed == 1, tokens, ratePerMs), nil
+}
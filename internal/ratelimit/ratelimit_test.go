@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"BigDataForge/internal/auth"
+)
+
+func TestParsePolicy(t *testing.T) {
+	for _, tc := range []struct {
+		value  string
+		want   Policy
+		failed bool
+	}{
+		{value: "60/m", want: Policy{Name: "test", Limit: 60, Period: time.Minute}},
+		{value: "10000/d", want: Policy{Name: "test", Limit: 10000, Period: 24 * time.Hour}},
+		{value: "5/s", want: Policy{Name: "test", Limit: 5, Period: time.Second}},
+		{value: "off", want: Policy{Name: "test"}},
+		{value: "60", failed: true},
+		{value: "60/w", failed: true},
+		{value: "0/m", failed: true},
+		{value: "-1/m", failed: true},
+		{value: "many/m", failed: true},
+		{value: "", failed: true},
+	} {
+		policy, err := ParsePolicy("test", tc.value)
+		if tc.failed {
+			if err == nil {
+				t.Errorf("ParsePolicy(%q) = %+v, want an error", tc.value, policy)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParsePolicy(%q) failed: %v", tc.value, err)
+			continue
+		}
+		if policy != tc.want {
+			t.Errorf("ParsePolicy(%q) = %+v, want %+v", tc.value, policy, tc.want)
+		}
+		if policy.Disabled() != (tc.value == "off") {
+			t.Errorf("ParsePolicy(%q).Disabled() = %v", tc.value, policy.Disabled())
+		}
+	}
+}
+
+func TestNewResult(t *testing.T) {
+	// 60/m refills one token per second
+	policy := Policy{Name: "test", Limit: 60, Period: time.Minute}
+	rate := float64(policy.Limit) / float64(policy.Period.Milliseconds())
+
+	for _, tc := range []struct {
+		name    string
+		allowed bool
+		tokens  float64
+		want    Result
+	}{
+		{
+			name: "first request", allowed: true, tokens: 59,
+			want: Result{Allowed: true, Limit: 60, Remaining: 59, Reset: time.Second},
+		},
+		{
+			name: "partial token", allowed: true, tokens: 29.5,
+			want: Result{Allowed: true, Limit: 60, Remaining: 29, Reset: 30500 * time.Millisecond},
+		},
+		{
+			name: "last token", allowed: true, tokens: 0,
+			want: Result{Allowed: true, Limit: 60, Remaining: 0, Reset: time.Minute},
+		},
+		{
+			name: "denied", allowed: false, tokens: 0.25,
+			want: Result{Allowed: false, Limit: 60, Remaining: 0, Reset: 59750 * time.Millisecond, RetryAfter: 750 * time.Millisecond},
+		},
+	} {
+		if got := newResult(policy, tc.allowed, tc.tokens, rate); got != tc.want {
+			t.Errorf("%s: newResult = %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestResultHeaders(t *testing.T) {
+	policy := Policy{Name: "test", Limit: 120, Period: time.Hour}
+
+	allowed := Result{Allowed: true, Limit: 120, Remaining: 7, Reset: 1500 * time.Millisecond}
+	assertHeaders(t, "allowed", allowed.Headers(policy), map[string]string{
+		"RateLimit-Limit":     "120",
+		"RateLimit-Remaining": "7",
+		"RateLimit-Reset":     "2",
+		"RateLimit-Policy":    "120;w=3600",
+	})
+
+	denied := Result{Allowed: false, Limit: 120, Remaining: 0, Reset: time.Hour, RetryAfter: 10*time.Second + time.Millisecond}
+	assertHeaders(t, "denied", denied.Headers(policy), map[string]string{
+		"RateLimit-Limit":     "120",
+		"RateLimit-Remaining": "0",
+		"RateLimit-Reset":     "3600",
+		"RateLimit-Policy":    "120;w=3600",
+		"Retry-After":         "11",
+	})
+}
+
+func assertHeaders(t *testing.T, name string, got, want map[string]string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s: headers = %v, want %v", name, got, want)
+	}
+	for header, value := range want {
+		if got[header] != value {
+			t.Errorf("%s: %s = %q, want %q", name, header, got[header], value)
+		}
+	}
+}
+
+func TestCallerKey(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		principal *auth.Principal
+		want      string
+	}{
+		{"anonymous", nil, "ip:192.0.2.1"},
+		{"token", &auth.Principal{Subject: "alice", Method: "oidc"}, "sub:oidc:alice"},
+		{"api key", &auth.Principal{Subject: "billing", Method: "apikey", Claims: map[string]interface{}{"kid": "k1"}}, "apikey:k1"},
+		{"hmac key", &auth.Principal{Subject: "billing", Method: "hmac", Claims: map[string]interface{}{"kid": "k2"}}, "hmac:k2"},
+		{"token with kid", &auth.Principal{Subject: "alice", Method: "oidc", Claims: map[string]interface{}{"kid": "k3"}}, "sub:oidc:alice"},
+	} {
+		if got := CallerKey(tc.principal, "192.0.2.1"); got != tc.want {
+			t.Errorf("%s: CallerKey = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Header carries the request ID in requests and responses.
+	Header = "X-Request-ID"
+
+	// Key stores the request ID in the gin context.
+	Key = "requestId"
+)
+
+// New generates a random request ID.
+func New() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
+
+// Get returns the request ID assigned to the current request.
+func Get(c *gin.Context) string {
+	return c.GetString(Key)
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the request ID, for service calls
+// and the messages they publish.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID carried by ctx, which may also be a gin
+// context holding it under Key, or "".
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok {
+		return id
+	}
+	id, _ := ctx.Value(Key).(string)
+	return id
+}
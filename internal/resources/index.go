@@ -0,0 +1,112 @@
+package resources
+
+// Document is a single Elasticsearch document produced from one object of a resource.
+type Document struct {
+	ID      string
+	Routing string
+	Body    map[string]interface{}
+}
+
+// Mapping builds the index mapping from the schema: one join field describing
+// the parent-child relations plus the scalar fields of every object.
+func (r *Resource) Mapping() map[string]interface{} {
+	properties := map[string]interface{}{}
+	relations := map[string]interface{}{}
+	r.collectMapping(r.Root, properties, relations)
+
+	properties[r.JoinField()] = map[string]interface{}{
+		"type":                  "join",
+		"eager_global_ordinals": true,
+		"relations":             relations,
+	}
+
+	return map[string]interface{}{
+		"mappings": map[string]interface{}{
+			"properties": properties,
+		},
+	}
+}
+
+func (r *Resource) collectMapping(node *Node, properties, relations map[string]interface{}) {
+	for field, fieldType := range node.Fields {
+		if _, ok := properties[field]; ok {
+			continue
+		}
+		if mapped := fieldMapping(field, fieldType); mapped != nil {
+			properties[field] = mapped
+		}
+	}
+
+	if len(node.Children) == 0 {
+		return
+	}
+	children := make([]string, 0, len(node.Children))
+	for _, child := range node.Children {
+		children = append(children, child.Relation)
+		r.collectMapping(child, properties, relations)
+	}
+	relations[node.Relation] = children
+}
+
+func fieldMapping(field, fieldType string) map[string]interface{} {
+	if field == "objectId" || field == "objectType" {
+		return map[string]interface{}{"type": "keyword"}
+	}
+	switch fieldType {
+	case "string":
+		return map[string]interface{}{
+			"type": "text",
+			"fields": map[string]interface{}{
+				"keyword": map[string]interface{}{"type": "keyword", "ignore_above": 256},
+			},
+		}
+	case "integer":
+		return map[string]interface{}{"type": "long"}
+	case "number":
+		return map[string]interface{}{"type": "double"}
+	case "boolean":
+		return map[string]interface{}{"type": "boolean"}
+	}
+	// Leave anything else to dynamic mapping
+	return nil
+}
+
+// Documents splits a document into one Elasticsearch document per object. All
+// of them are routed by the root objectId so the whole tree shares a shard.
+func (r *Resource) Documents(doc map[string]interface{}) []Document {
+	rootID := ObjectID(doc)
+	var documents []Document
+	r.collectDocuments(r.Root, doc, "", rootID, &documents)
+	return documents
+}
+
+func (r *Resource) collectDocuments(node *Node, doc map[string]interface{}, parentID, rootID string, documents *[]Document) {
+	id := ObjectID(doc)
+
+	body := make(map[string]interface{}, len(node.Fields)+1)
+	for field := range node.Fields {
+		if value, ok := doc[field]; ok {
+			body[field] = value
+		}
+	}
+	join := map[string]interface{}{"name": node.Relation}
+	if parentID != "" {
+		join["parent"] = parentID
+	}
+	body[r.JoinField()] = join
+
+	*documents = append(*documents, Document{ID: id, Routing: rootID, Body: body})
+
+	for _, child := range node.Children {
+		switch value := doc[child.Property].(type) {
+		case map[string]interface{}:
+			r.collectDocuments(child, value, id, rootID, documents)
+		case []interface{}:
+			for _, item := range value {
+				if nested, ok := item.(map[string]interface{}); ok {
+					r.collectDocuments(child, nested, id, rootID, documents)
+				}
+			}
+		}
+	}
+}
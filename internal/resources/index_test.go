@@ -0,0 +1,75 @@
+package resources
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDocuments(t *testing.T) {
+	resource := newPolicy(t)
+	documents := resource.Documents(decode(t, policyDoc))
+
+	type summary struct {
+		ID, Routing, Relation, Parent string
+	}
+	got := make([]summary, 0, len(documents))
+	for _, document := range documents {
+		if document.Routing != "p1" {
+			t.Errorf("document %s is routed by %s, want the root objectId", document.ID, document.Routing)
+		}
+		join := document.Body["policy_join"].(map[string]interface{})
+		parent, _ := join["parent"].(string)
+		got = append(got, summary{document.ID, document.Routing, join["name"].(string), parent})
+	}
+	want := []summary{
+		{"p1", "p1", "policy", ""},
+		{"c1", "p1", "coverages", "p1"},
+		{"l1", "p1", "limits", "c1"},
+		{"l2", "p1", "limits", "c1"},
+		{"c2", "p1", "coverages", "p1"},
+		{"o1", "p1", "owner", "p1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Documents = %v, want %v", got, want)
+	}
+
+	// Bodies hold the scalar fields of their own object only
+	root := documents[0].Body
+	if _, ok := root["coverages"]; ok {
+		t.Error("the root document embeds its children")
+	}
+	if root["name"] != "Home" || !reflect.DeepEqual(root["tags"], []interface{}{"home", "fire"}) {
+		t.Errorf("root body = %v", root)
+	}
+	if limit := documents[2].Body; limit["amount"] == nil || limit["kind"] != nil {
+		t.Errorf("limit body = %v", limit)
+	}
+}
+
+func TestMapping(t *testing.T) {
+	resource := newPolicy(t)
+	properties := resource.Mapping()["mappings"].(map[string]interface{})["properties"].(map[string]interface{})
+
+	join := properties["policy_join"].(map[string]interface{})
+	wantRelations := map[string]interface{}{
+		"policy":    []string{"coverages", "owner"},
+		"coverages": []string{"limits"},
+	}
+	if join["type"] != "join" || !reflect.DeepEqual(join["relations"], wantRelations) {
+		t.Errorf("join field = %v", join)
+	}
+	for field, want := range map[string]string{
+		"objectId": "keyword",
+		"name":     "text",
+		"premium":  "double",
+		"amount":   "long",
+	} {
+		mapped, _ := properties[field].(map[string]interface{})
+		if mapped["type"] != want {
+			t.Errorf("%s is mapped as %v, want %s", field, mapped["type"], want)
+		}
+	}
+	if _, ok := properties["tags"]; ok {
+		t.Error("an array field has an explicit mapping")
+	}
+}
@@ -0,0 +1,67 @@
+package resources
+
+// Merge applies a partial document onto an existing one. Scalar fields are
+// overwritten, child objects with a matching objectId are merged recursively
+// and new items of child arrays are appended.
+func (r *Resource) Merge(existing, patch map[string]interface{}) map[string]interface{} {
+	return mergeNode(r.Root, existing, patch)
+}
+
+func mergeNode(node *Node, existing, patch map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(existing))
+	for field, value := range existing {
+		merged[field] = value
+	}
+
+	children := make(map[string]*Node, len(node.Children))
+	for _, child := range node.Children {
+		children[child.Property] = child
+	}
+
+	for field, value := range patch {
+		child, isChild := children[field]
+		if !isChild {
+			merged[field] = value
+			continue
+		}
+
+		if !child.Many {
+			current, _ := merged[field].(map[string]interface{})
+			incoming, ok := value.(map[string]interface{})
+			if ok && current != nil && ObjectID(current) == ObjectID(incoming) {
+				merged[field] = mergeNode(child, current, incoming)
+			} else {
+				merged[field] = value
+			}
+			continue
+		}
+
+		current, _ := merged[field].([]interface{})
+		incoming, ok := value.([]interface{})
+		if !ok {
+			merged[field] = value
+			continue
+		}
+		items := append([]interface{}{}, current...)
+		for _, item := range incoming {
+			nested, ok := item.(map[string]interface{})
+			if !ok {
+				items = append(items, item)
+				continue
+			}
+			replaced := false
+			for i, existingItem := range items {
+				if existingNested, ok := existingItem.(map[string]interface{}); ok && ObjectID(existingNested) == ObjectID(nested) {
+					items[i] = mergeNode(child, existingNested, nested)
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				items = append(items, nested)
+			}
+		}
+		merged[field] = items
+	}
+	return merged
+}
@@ -0,0 +1,74 @@
+package resources
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	resource := newPolicy(t)
+
+	for _, tc := range []struct {
+		name  string
+		patch string
+		want  string
+	}{
+		{
+			"scalar fields are overwritten",
+			`{"name": "Cabin", "premium": 99}`,
+			`{"objectId": "p1", "objectType": "policy", "name": "Cabin", "premium": 99, "tags": ["home"],
+				"owner": {"objectId": "o1", "objectType": "owner", "email": "a@a.com"},
+				"coverages": [{"objectId": "c1", "objectType": "coverage", "kind": "fire", "limits": [{"objectId": "l1", "objectType": "limit", "amount": 1000}]}]}`,
+		},
+		{
+			"arrays of scalars are replaced",
+			`{"tags": ["cabin"]}`,
+			`{"objectId": "p1", "objectType": "policy", "name": "Home", "tags": ["cabin"],
+				"owner": {"objectId": "o1", "objectType": "owner", "email": "a@a.com"},
+				"coverages": [{"objectId": "c1", "objectType": "coverage", "kind": "fire", "limits": [{"objectId": "l1", "objectType": "limit", "amount": 1000}]}]}`,
+		},
+		{
+			"a child with the same objectId is merged",
+			`{"owner": {"objectId": "o1", "email": "b@b.com"}}`,
+			`{"objectId": "p1", "objectType": "policy", "name": "Home", "tags": ["home"],
+				"owner": {"objectId": "o1", "objectType": "owner", "email": "b@b.com"},
+				"coverages": [{"objectId": "c1", "objectType": "coverage", "kind": "fire", "limits": [{"objectId": "l1", "objectType": "limit", "amount": 1000}]}]}`,
+		},
+		{
+			"a child with another objectId replaces it",
+			`{"owner": {"objectId": "o2", "objectType": "owner"}}`,
+			`{"objectId": "p1", "objectType": "policy", "name": "Home", "tags": ["home"],
+				"owner": {"objectId": "o2", "objectType": "owner"},
+				"coverages": [{"objectId": "c1", "objectType": "coverage", "kind": "fire", "limits": [{"objectId": "l1", "objectType": "limit", "amount": 1000}]}]}`,
+		},
+		{
+			"array items are merged by objectId at any depth and new ones appended",
+			`{"coverages": [
+				{"objectId": "c1", "limits": [{"objectId": "l1", "amount": 1500}, {"objectId": "l2", "objectType": "limit", "amount": 10}]},
+				{"objectId": "c2", "objectType": "coverage", "kind": "flood"}
+			]}`,
+			`{"objectId": "p1", "objectType": "policy", "name": "Home", "tags": ["home"],
+				"owner": {"objectId": "o1", "objectType": "owner", "email": "a@a.com"},
+				"coverages": [
+					{"objectId": "c1", "objectType": "coverage", "kind": "fire", "limits": [
+						{"objectId": "l1", "objectType": "limit", "amount": 1500},
+						{"objectId": "l2", "objectType": "limit", "amount": 10}
+					]},
+					{"objectId": "c2", "objectType": "coverage", "kind": "flood"}
+				]}`,
+		},
+	} {
+		existing := decode(t, `{"objectId": "p1", "objectType": "policy", "name": "Home", "tags": ["home"],
+			"owner": {"objectId": "o1", "objectType": "owner", "email": "a@a.com"},
+			"coverages": [{"objectId": "c1", "objectType": "coverage", "kind": "fire", "limits": [{"objectId": "l1", "objectType": "limit", "amount": 1000}]}]}`)
+		before := ETag(existing)
+
+		merged := resource.Merge(existing, decode(t, tc.patch))
+		if want := decode(t, tc.want); !reflect.DeepEqual(merged, want) {
+			t.Errorf("%s: Merge = %v, want %v", tc.name, merged, want)
+		}
+		if ETag(existing) != before {
+			t.Errorf("%s: Merge modified the existing document", tc.name)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Top-level segments of the /api/v1 routes served by dedicated handlers; a
+// resource cannot claim them, since its routes would collide with theirs.
+// Routes added under a new segment must reserve it here.
+var reservedPaths = map[string]bool{
+	"plans":        true,
+	"search":       true,
+	"webhooks":     true,
+	"admin":        true,
+	"openapi.json": true,
+	"docs":         true,
+}
+
+// Reserved reports whether a top-level /api/v1 segment is served by dedicated handlers.
+func Reserved(segment string) bool {
+	return reservedPaths[segment]
+}
+
+// Node describes one objectType/objectId level of a resource, derived from the
+// nested objects of its JSON Schema.
+type Node struct {
+	Relation string            // join relation name used in Elasticsearch
+	Property string            // property holding this node in its parent, empty for the root
+	Many     bool              // true when the property is an array of objects
+	Fields   map[string]string // scalar property name -> JSON Schema type
+	Children []*Node
+}
+
+// Resource is a registered document type with its compiled schemas and relation tree.
+type Resource struct {
+	Name        string
+	Path        string
+	Index       string
+	Schema      *gojsonschema.Schema
+	PatchSchema *gojsonschema.Schema
+	RawSchema   []byte // the JSON Schema the resource was registered with
+	Root        *Node
+}
+
+// JoinField is the name of the Elasticsearch join field for the resource.
+func (r *Resource) JoinField() string {
+	return r.Name + "_join"
+}
+
+// Registry holds the resource types known to the API.
+type Registry struct {
+	mu        sync.RWMutex
+	resources map[string]*Resource
+}
+
+func NewRegistry() *Registry {
+	return &Registry{resources: make(map[string]*Resource)}
+}
+
+// Register compiles the schema and derives the relation tree for a new resource type.
+func (r *Registry) Register(name string, schema []byte) (*Resource, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(schema, &raw); err != nil {
+		return nil, fmt.Errorf("resource %s: invalid schema JSON: %w", name, err)
+	}
+
+	root, err := buildNode(name, "", false, raw)
+	if err != nil {
+		return nil, fmt.Errorf("resource %s: %w", name, err)
+	}
+	if err := checkRelations(root, map[string]bool{}); err != nil {
+		return nil, fmt.Errorf("resource %s: %w", name, err)
+	}
+
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("resource %s: failed to compile schema: %w", name, err)
+	}
+
+	// Partial updates may omit any top-level property
+	patchRaw := make(map[string]interface{}, len(raw))
+	for k, v := range raw {
+		if k != "required" {
+			patchRaw[k] = v
+		}
+	}
+	compiledPatch, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(patchRaw))
+	if err != nil {
+		return nil, fmt.Errorf("resource %s: failed to compile patch schema: %w", name, err)
+	}
+
+	resource := &Resource{
+		Name:        name,
+		Path:        name + "s",
+		Index:       name + "s",
+		Schema:      compiled,
+		PatchSchema: compiledPatch,
+		RawSchema:   schema,
+		Root:        root,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if reservedPaths[resource.Path] {
+		return nil, fmt.Errorf("resource %s: path /%s is reserved", name, resource.Path)
+	}
+	if _, exists := r.resources[name]; exists {
+		return nil, fmt.Errorf("resource %s is already registered", name)
+	}
+	r.resources[name] = resource
+	return resource, nil
+}
+
+// LoadDir registers every *.json schema in dir, naming each resource after its file.
+func (r *Registry) LoadDir(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		schema, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		name = strings.TrimSuffix(name, "_schema")
+		if _, err := r.Register(name, schema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get returns a registered resource by name.
+func (r *Registry) Get(name string) (*Resource, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	resource, ok := r.resources[name]
+	return resource, ok
+}
+
+// All returns the registered resources sorted by name.
+func (r *Registry) All() []*Resource {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	all := make([]*Resource, 0, len(r.resources))
+	for _, resource := range r.resources {
+		all = append(all, resource)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
+
+// buildNode walks an object schema, splitting its properties into scalar fields
+// and child nodes. Any nested object carrying an objectId becomes a child.
+func buildNode(relation, property string, many bool, schema map[string]interface{}) (*Node, error) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	if !hasIdentity(properties) {
+		return nil, fmt.Errorf("object %q must declare objectId and objectType properties", relation)
+	}
+
+	node := &Node{
+		Relation: relation,
+		Property: property,
+		Many:     many,
+		Fields:   make(map[string]string),
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, _ := properties[name].(map[string]interface{})
+		propType, _ := prop["type"].(string)
+
+		switch propType {
+		case "object":
+			if nested, _ := prop["properties"].(map[string]interface{}); hasIdentity(nested) {
+				child, err := buildNode(name, name, false, prop)
+				if err != nil {
+					return nil, err
+				}
+				node.Children = append(node.Children, child)
+				continue
+			}
+		case "array":
+			items, _ := prop["items"].(map[string]interface{})
+			if nested, _ := items["properties"].(map[string]interface{}); items["type"] == "object" && hasIdentity(nested) {
+				child, err := buildNode(name, name, true, items)
+				if err != nil {
+					return nil, err
+				}
+				node.Children = append(node.Children, child)
+				continue
+			}
+		}
+		node.Fields[name] = propType
+	}
+	return node, nil
+}
+
+func hasIdentity(properties map[string]interface{}) bool {
+	_, hasID := properties["objectId"]
+	_, hasType := properties["objectType"]
+	return hasID && hasType
+}
+
+// Join relation names must be unique across the tree for Elasticsearch
+func checkRelations(node *Node, seen map[string]bool) error {
+	if seen[node.Relation] {
+		return fmt.Errorf("relation %q appears more than once", node.Relation)
+	}
+	seen[node.Relation] = true
+	for _, child := range node.Children {
+		if err := checkRelations(child, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package resources
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// Schema of a policy with one owner and many coverages, each with many limits
+const policySchema = `{
+	"type": "object",
+	"required": ["objectId", "objectType", "name"],
+	"properties": {
+		"objectId": {"type": "string"},
+		"objectType": {"type": "string"},
+		"name": {"type": "string"},
+		"premium": {"type": "number"},
+		"owner": {
+			"type": "object",
+			"properties": {
+				"objectId": {"type": "string"},
+				"objectType": {"type": "string"},
+				"email": {"type": "string"}
+			}
+		},
+		"coverages": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"objectId": {"type": "string"},
+					"objectType": {"type": "string"},
+					"kind": {"type": "string"},
+					"limits": {
+						"type": "array",
+						"items": {
+							"type": "object",
+							"properties": {
+								"objectId": {"type": "string"},
+								"objectType": {"type": "string"},
+								"amount": {"type": "integer"}
+							}
+						}
+					}
+				}
+			}
+		},
+		"tags": {"type": "array", "items": {"type": "string"}}
+	}
+}`
+
+// Helper to register the policy resource
+func newPolicy(t *testing.T) *Resource {
+	t.Helper()
+	resource, err := NewRegistry().Register("policy", []byte(policySchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resource
+}
+
+// Helper to decode a JSON document the way the handlers do
+func decode(t *testing.T, doc string) map[string]interface{} {
+	t.Helper()
+	var decoded map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(doc))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestRegisterBuildsRelationTree(t *testing.T) {
+	resource := newPolicy(t)
+
+	root := resource.Root
+	if root.Relation != "policy" || len(root.Children) != 2 {
+		t.Fatalf("root = %+v, want policy with two children", root)
+	}
+	coverages, owner := root.Children[0], root.Children[1]
+	if coverages.Property != "coverages" || !coverages.Many || len(coverages.Children) != 1 || coverages.Children[0].Relation != "limits" {
+		t.Errorf("coverages = %+v", coverages)
+	}
+	if owner.Property != "owner" || owner.Many {
+		t.Errorf("owner = %+v", owner)
+	}
+	if _, ok := root.Fields["tags"]; !ok {
+		t.Error("an array of strings is not a scalar field of the root")
+	}
+	if resource.Path != "policys" || resource.Index != "policys" || resource.JoinField() != "policy_join" {
+		t.Errorf("path %s, index %s, join field %s", resource.Path, resource.Index, resource.JoinField())
+	}
+}
+
+func TestRegisterRejectsInvalidSchemas(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{"doc", policySchema, "reserved"},
+		{"policy", `{"type": "object"`, "invalid schema JSON"},
+		{"policy", `{"type": "object", "properties": {"objectId": {"type": "string"}}}`, "must declare objectId and objectType"},
+		{"policy", `{"type": "object", "properties": {
+			"objectId": {"type": "string"}, "objectType": {"type": "string"},
+			"policy": {"type": "object", "properties": {"objectId": {"type": "string"}, "objectType": {"type": "string"}}}
+		}}`, "appears more than once"},
+	} {
+		if _, err := NewRegistry().Register(tc.name, []byte(tc.schema)); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("Register(%s) = %v, want an error about %s", tc.name, err, tc.wantErr)
+		}
+	}
+
+	registry := NewRegistry()
+	if _, err := registry.Register("policy", []byte(policySchema)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := registry.Register("policy", []byte(policySchema)); err == nil {
+		t.Error("a resource was registered twice")
+	}
+}
@@ -0,0 +1,212 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Each object of a document is stored under its own key. Child properties hold
+// the keys of the stored children instead of the nested objects. Redis layout:
+//
+//	resource:<name>:<rootId>                      root object
+//	resource:<name>:<rootId>:<relation>:<id>      child object
+//
+// The prefix keeps resource names out of the keyspace of API keys, webhooks,
+// idempotency records and the other stores.
+const keyPrefix = "resource:"
+
+// RootKey is the Redis key of a document's root object.
+func (r *Resource) RootKey(id string) string {
+	return keyPrefix + r.Name + ":" + id
+}
+
+func (r *Resource) childKey(rootID string, node *Node, id string) string {
+	return fmt.Sprintf("%s%s:%s:%s:%s", keyPrefix, r.Name, rootID, node.Relation, id)
+}
+
+// Exists reports whether a document with the given root objectId is stored.
+func (r *Resource) Exists(ctx context.Context, client *redis.Client, id string) (bool, error) {
+	n, err := client.Exists(ctx, r.RootKey(id)).Result()
+	return n > 0, err
+}
+
+// Save de-structures the document and writes every object in one transaction.
+func (r *Resource) Save(ctx context.Context, client *redis.Client, doc map[string]interface{}) error {
+	rootID := ObjectID(doc)
+	records := make(map[string]interface{})
+	if err := r.flatten(rootID, r.Root, r.RootKey(rootID), doc, records); err != nil {
+		return err
+	}
+
+	_, err := client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, record := range records {
+			data, err := json.Marshal(record)
+			if err != nil {
+				return err
+			}
+			pipe.Set(ctx, key, data, 0)
+		}
+		return nil
+	})
+	return err
+}
+
+func (r *Resource) flatten(rootID string, node *Node, key string, doc map[string]interface{}, records map[string]interface{}) error {
+	record := make(map[string]interface{}, len(doc))
+	for field, value := range doc {
+		record[field] = value
+	}
+
+	for _, child := range node.Children {
+		value, ok := doc[child.Property]
+		if !ok {
+			continue
+		}
+		if !child.Many {
+			nested, ok := value.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("property %s must be an object", child.Property)
+			}
+			childKey := r.childKey(rootID, child, ObjectID(nested))
+			if err := r.flatten(rootID, child, childKey, nested, records); err != nil {
+				return err
+			}
+			record[child.Property] = childKey
+			continue
+		}
+
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("property %s must be an array", child.Property)
+		}
+		keys := make([]string, 0, len(items))
+		for _, item := range items {
+			nested, ok := item.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("items of %s must be objects", child.Property)
+			}
+			childKey := r.childKey(rootID, child, ObjectID(nested))
+			if err := r.flatten(rootID, child, childKey, nested, records); err != nil {
+				return err
+			}
+			keys = append(keys, childKey)
+		}
+		record[child.Property] = keys
+	}
+
+	records[key] = record
+	return nil
+}
+
+// Load reassembles a stored document. It returns nil when the document does not exist.
+func (r *Resource) Load(ctx context.Context, client *redis.Client, id string) (map[string]interface{}, error) {
+	doc, _, err := r.load(ctx, client, r.Root, r.RootKey(id))
+	if err == redis.Nil {
+		return nil, nil
+	}
+	return doc, err
+}
+
+// load returns the assembled object and every key it was built from.
+func (r *Resource) load(ctx context.Context, client *redis.Client, node *Node, key string) (map[string]interface{}, []string, error) {
+	data, err := client.Get(ctx, key).Bytes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var doc map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, nil, err
+	}
+
+	keys := []string{key}
+	for _, child := range node.Children {
+		switch ref := doc[child.Property].(type) {
+		case string:
+			nested, nestedKeys, err := r.load(ctx, client, child, ref)
+			if err != nil {
+				return nil, nil, err
+			}
+			doc[child.Property] = nested
+			keys = append(keys, nestedKeys...)
+		case []interface{}:
+			items := make([]interface{}, 0, len(ref))
+			for _, itemKey := range ref {
+				nested, nestedKeys, err := r.load(ctx, client, child, fmt.Sprint(itemKey))
+				if err != nil {
+					return nil, nil, err
+				}
+				items = append(items, nested)
+				keys = append(keys, nestedKeys...)
+			}
+			doc[child.Property] = items
+		}
+	}
+	return doc, keys, nil
+}
+
+// Delete removes every stored object of a document.
+func (r *Resource) Delete(ctx context.Context, client *redis.Client, id string) error {
+	_, keys, err := r.load(ctx, client, r.Root, r.RootKey(id))
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return client.Del(ctx, keys...).Err()
+}
+
+// Replace swaps a stored document for a new version in one transaction.
+func (r *Resource) Replace(ctx context.Context, client *redis.Client, id string, doc map[string]interface{}) error {
+	_, oldKeys, err := r.load(ctx, client, r.Root, r.RootKey(id))
+	if err != nil && err != redis.Nil {
+		return err
+	}
+
+	rootID := ObjectID(doc)
+	records := make(map[string]interface{})
+	if err := r.flatten(rootID, r.Root, r.RootKey(rootID), doc, records); err != nil {
+		return err
+	}
+
+	_, err = client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if len(oldKeys) > 0 {
+			pipe.Del(ctx, oldKeys...)
+		}
+		for key, record := range records {
+			data, err := json.Marshal(record)
+			if err != nil {
+				return err
+			}
+			pipe.Set(ctx, key, data, 0)
+		}
+		return nil
+	})
+	return err
+}
+
+// ETag hashes the assembled document. Map keys are marshalled in sorted order,
+// so the result is stable for equal documents.
+func ETag(doc map[string]interface{}) string {
+	data, _ := json.Marshal(doc)
+	hash := sha1.New()
+	hash.Write(data)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// ObjectID returns the objectId of a decoded object as a string.
+func ObjectID(doc map[string]interface{}) string {
+	if id, ok := doc["objectId"]; ok && id != nil {
+		return fmt.Sprint(id)
+	}
+	return ""
+}
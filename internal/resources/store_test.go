@@ -0,0 +1,141 @@
+package resources
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+const policyDoc = `{
+	"objectId": "p1",
+	"objectType": "policy",
+	"name": "Home",
+	"premium": 120.5,
+	"tags": ["home", "fire"],
+	"owner": {"objectId": "o1", "objectType": "owner", "email": "a@a.com"},
+	"coverages": [
+		{"objectId": "c1", "objectType": "coverage", "kind": "fire", "limits": [
+			{"objectId": "l1", "objectType": "limit", "amount": 1000},
+			{"objectId": "l2", "objectType": "limit", "amount": 2000}
+		]},
+		{"objectId": "c2", "objectType": "coverage", "kind": "flood"}
+	]
+}`
+
+func TestFlatten(t *testing.T) {
+	resource := newPolicy(t)
+	records := make(map[string]interface{})
+	if err := resource.flatten("p1", resource.Root, resource.RootKey("p1"), decode(t, policyDoc), records); err != nil {
+		t.Fatal(err)
+	}
+
+	wantKeys := []string{
+		"resource:policy:p1",
+		"resource:policy:p1:owner:o1",
+		"resource:policy:p1:coverages:c1",
+		"resource:policy:p1:coverages:c2",
+		"resource:policy:p1:limits:l1",
+		"resource:policy:p1:limits:l2",
+	}
+	if len(records) != len(wantKeys) {
+		t.Errorf("flatten stored %d records, want %d", len(records), len(wantKeys))
+	}
+	for _, key := range wantKeys {
+		if _, ok := records[key]; !ok {
+			t.Errorf("no record stored under %s", key)
+		}
+	}
+
+	// Children are replaced by the keys of their records
+	root := records["resource:policy:p1"].(map[string]interface{})
+	if root["owner"] != "resource:policy:p1:owner:o1" {
+		t.Errorf("root owner = %v", root["owner"])
+	}
+	if want := []string{"resource:policy:p1:coverages:c1", "resource:policy:p1:coverages:c2"}; !reflect.DeepEqual(root["coverages"], want) {
+		t.Errorf("root coverages = %v, want %v", root["coverages"], want)
+	}
+	if !reflect.DeepEqual(root["tags"], []interface{}{"home", "fire"}) {
+		t.Errorf("root tags = %v, want the array kept as a field", root["tags"])
+	}
+	coverage := records["resource:policy:p1:coverages:c1"].(map[string]interface{})
+	if want := []string{"resource:policy:p1:limits:l1", "resource:policy:p1:limits:l2"}; !reflect.DeepEqual(coverage["limits"], want) {
+		t.Errorf("coverage limits = %v, want %v", coverage["limits"], want)
+	}
+}
+
+func TestFlattenRejectsMistypedChildren(t *testing.T) {
+	resource := newPolicy(t)
+	for _, tc := range []struct {
+		doc     string
+		wantErr string
+	}{
+		{`{"objectId": "p1", "owner": "o1"}`, "owner must be an object"},
+		{`{"objectId": "p1", "coverages": {"objectId": "c1"}}`, "coverages must be an array"},
+		{`{"objectId": "p1", "coverages": ["c1"]}`, "items of coverages must be objects"},
+	} {
+		err := resource.flatten("p1", resource.Root, resource.RootKey("p1"), decode(t, tc.doc), map[string]interface{}{})
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("flatten(%s) = %v, want an error about %s", tc.doc, err, tc.wantErr)
+		}
+	}
+}
+
+// TestKeysStayInTheResourceNamespace fails when a resource name can address
+// the keys of another store, such as the API keys.
+func TestKeysStayInTheResourceNamespace(t *testing.T) {
+	resource, err := NewRegistry().Register("apikey", []byte(`{"type": "object", "properties": {
+		"objectId": {"type": "string"}, "objectType": {"type": "string"}
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key := resource.RootKey("abc"); key != "resource:apikey:abc" {
+		t.Errorf("RootKey = %s, want resource:apikey:abc", key)
+	}
+}
+
+func TestSaveLoadReplaceDelete(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	ctx := context.Background()
+	resource := newPolicy(t)
+
+	doc := decode(t, policyDoc)
+	if err := resource.Save(ctx, client, doc); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := resource.Load(ctx, client, "p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, doc) {
+		t.Errorf("Load = %v, want %v", loaded, doc)
+	}
+	if ETag(loaded) != ETag(doc) {
+		t.Error("the loaded document has another ETag")
+	}
+
+	// Replacing drops the objects the new version no longer holds
+	replacement := decode(t, `{"objectId": "p1", "objectType": "policy", "name": "Home", "coverages": [{"objectId": "c2", "objectType": "coverage", "kind": "flood"}]}`)
+	if err := resource.Replace(ctx, client, "p1", replacement); err != nil {
+		t.Fatal(err)
+	}
+	if keys := server.Keys(); !reflect.DeepEqual(keys, []string{"resource:policy:p1", "resource:policy:p1:coverages:c2"}) {
+		t.Errorf("keys after Replace = %v", keys)
+	}
+
+	if err := resource.Delete(ctx, client, "p1"); err != nil {
+		t.Fatal(err)
+	}
+	if keys := server.Keys(); len(keys) != 0 {
+		t.Errorf("keys after Delete = %v, want none", keys)
+	}
+	if loaded, err := resource.Load(ctx, client, "p1"); loaded != nil || err != nil {
+		t.Errorf("Load after Delete = %v, %v, want nil, nil", loaded, err)
+	}
+}
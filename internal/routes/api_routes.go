@@ -1,25 +1,125 @@
 package routes
 
 import (
+	"net/http"
+
+	"BigDataForge/internal/auth"
 	"BigDataForge/internal/controllers"
 	"BigDataForge/internal/elastic"
+	"BigDataForge/internal/graphqlapi"
+	"BigDataForge/internal/health"
+	"BigDataForge/internal/idempotency"
+	"BigDataForge/internal/metrics"
 	"BigDataForge/internal/middlewares"
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/ratelimit"
+	"BigDataForge/internal/resources"
+	"BigDataForge/internal/schemas"
+	"BigDataForge/internal/services"
+	"BigDataForge/internal/tenancy"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
 )
 
-func SetupRoutes(router *gin.Engine, redisClient *redis.Client, esFactory *elastic.Factory) {
-	planController := controllers.NewPlanController(redisClient, esFactory)
+func SetupRoutes(router *gin.Engine, redisClient *redis.Client, esFactory *elastic.Factory, registry *resources.Registry, schemaRegistry *schemas.Registry, authenticator auth.Authenticator, resolver *tenancy.Resolver, rateLimits ratelimit.Policies, idempotencyStore *idempotency.Store, graphqlLimits graphqlapi.Limits, checker *health.Checker, changeFeed *services.ChangeFeedService) {
+	planController := controllers.NewPlanController(redisClient, esFactory, schemaRegistry)
+	schemaController := controllers.NewSchemaController(schemaRegistry)
+	credentialController := controllers.NewCredentialController(redisClient)
+	membershipController := controllers.NewMembershipController(resolver.Store)
+	auditController := controllers.NewAuditController(redisClient)
+	changeFeedController := controllers.NewChangeFeedController(changeFeed)
+	webhookController := controllers.NewWebhookController(redisClient)
+	graphqlController := controllers.NewGraphQLController(redisClient, esFactory, schemaRegistry, graphqlLimits)
+	openapiController := controllers.NewOpenAPIController(router, schemaRegistry, registry)
+	healthController := controllers.NewHealthController(checker)
+
+	limiter := ratelimit.NewLimiter(redisClient)
+	ipLimit := middlewares.IPRateLimitMiddleware(limiter, rateLimits.IP)
+	searchLimit := middlewares.RateLimitMiddleware(limiter, rateLimits.Search)
+	writeLimit := middlewares.RateLimitMiddleware(limiter, rateLimits.Write)
+	idempotent := middlewares.IdempotencyMiddleware(idempotencyStore)
+
+	router.Use(middlewares.MetricsMiddleware())
+	router.Use(middlewares.TracingMiddleware())
+	router.Use(middlewares.RequestIDMiddleware())
+	router.NoRoute(func(c *gin.Context) {
+		problems.Abort(c, http.StatusNotFound, problems.CodeNotFound, "Route not found")
+	})
+
+	// Probes and metrics are public and outside /api/v1, so they are neither authenticated nor rate limited
+	router.GET("/healthz", healthController.Live)
+	router.GET("/readyz", healthController.Ready)
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// The API description is public; it is generated from the routes registered below
+	router.GET("/api/v1/openapi.json", openapiController.GetDocument)
+	router.GET("/api/v1/docs", openapiController.GetDocs)
 
 	api := router.Group("/api/v1")
-	api.Use(middlewares.AuthMiddleware()) // Apply AuthMiddleware to protect all routes in this group
+	api.Use(ipLimit)                                   // Limit clients before authenticating them, so failed attempts count too
+	api.Use(middlewares.AuthMiddleware(authenticator)) // Apply AuthMiddleware to protect all routes in this group
+	api.Use(middlewares.TenancyMiddleware(resolver))   // Resolve org memberships; services enforce them per object
+	api.Use(middlewares.RateLimitMiddleware(limiter, rateLimits.Default))
 	{
-		api.POST("/plans", planController.CreatePlan)
+		api.POST("/plans", writeLimit, idempotent, planController.CreatePlan)
 		api.GET("/plans", planController.GetPlan)
-		api.DELETE("/plans", planController.DeletePlan)
-		api.PATCH("/plans", planController.PatchPlan)
-		api.PUT("/plans", planController.UpdatePlan)
-		api.POST("/search", planController.SearchPlans)
+		api.DELETE("/plans", writeLimit, idempotent, planController.DeletePlan)
+		api.PATCH("/plans", writeLimit, idempotent, planController.PatchPlan)
+		api.PUT("/plans", writeLimit, idempotent, planController.UpdatePlan)
+		api.GET("/plans/changes", changeFeedController.StreamChanges)
+		api.POST("/search", searchLimit, planController.SearchPlans)
+
+		api.GET("/webhooks", webhookController.ListWebhooks)
+		api.POST("/webhooks", webhookController.CreateWebhook)
+		api.GET("/webhooks/:id", webhookController.GetWebhook)
+		api.DELETE("/webhooks/:id", webhookController.DeleteWebhook)
+		api.POST("/webhooks/:id/enable", webhookController.EnableWebhook)
+		api.GET("/webhooks/:id/deliveries", webhookController.ListWebhookDeliveries)
+	}
+
+	// GraphQL shares the rate limits, authentication and org resolution of /api/v1
+	router.POST("/graphql",
+		ipLimit,
+		middlewares.AuthMiddleware(authenticator),
+		middlewares.TenancyMiddleware(resolver),
+		middlewares.RateLimitMiddleware(limiter, rateLimits.Default),
+		graphqlController.Query)
+
+	admin := api.Group("/admin")
+	admin.Use(middlewares.RequireRole(tenancy.GlobalOrg, tenancy.RoleAdmin))
+	{
+		admin.GET("/schemas", schemaController.ListSchemas)
+		admin.GET("/schemas/:name", schemaController.ListSchemaVersions)
+		admin.POST("/schemas/:name", schemaController.UploadSchema)
+		admin.GET("/schemas/:name/versions/:version", schemaController.GetSchemaVersion)
+		admin.PUT("/schemas/:name/active", schemaController.ActivateSchema)
+
+		admin.GET("/apikeys", credentialController.ListAPIKeys)
+		admin.POST("/apikeys", credentialController.CreateAPIKey)
+		admin.DELETE("/apikeys/:id", credentialController.RevokeAPIKey)
+		admin.POST("/hmackeys", credentialController.CreateHMACKey)
+		admin.DELETE("/hmackeys/:id", credentialController.RevokeHMACKey)
+
+		admin.GET("/members/:subject", membershipController.GetMemberships)
+		admin.PUT("/members/:subject/orgs/:org", membershipController.GrantMembership)
+		admin.DELETE("/members/:subject/orgs/:org", membershipController.RevokeMembership)
+
+		admin.GET("/audit", auditController.QueryAuditLog)
+		admin.GET("/audit/export", auditController.ExportAuditLog)
+
+		admin.GET("/status", healthController.Status)
+	}
+
+	// Every schema-registered resource gets the same set of CRUD routes
+	for _, resource := range registry.All() {
+		resourceController := controllers.NewResourceController(redisClient, esFactory, resource)
+		path := "/" + resource.Path
+
+		api.POST(path, writeLimit, idempotent, resourceController.CreateResource)
+		api.GET(path, resourceController.GetResource)
+		api.DELETE(path, writeLimit, idempotent, resourceController.DeleteResource)
+		api.PATCH(path, writeLimit, idempotent, resourceController.PatchResource)
+		api.PUT(path, writeLimit, idempotent, resourceController.UpdateResource)
 	}
 }
@@ -0,0 +1,113 @@
+package routes
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"BigDataForge/internal/auth"
+	"BigDataForge/internal/changefeed"
+	"BigDataForge/internal/elastic"
+	"BigDataForge/internal/graphqlapi"
+	"BigDataForge/internal/health"
+	"BigDataForge/internal/idempotency"
+	"BigDataForge/internal/openapi"
+	"BigDataForge/internal/ratelimit"
+	"BigDataForge/internal/resources"
+	"BigDataForge/internal/schemas"
+	"BigDataForge/internal/services"
+	"BigDataForge/internal/tenancy"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+// A resource schema so the generated resource routes are covered too
+const policySchema = `{
+	"type": "object",
+	"properties": {
+		"objectId": {"type": "string"},
+		"objectType": {"type": "string"},
+		"_org": {"type": "string"},
+		"name": {"type": "string"}
+	},
+	"required": ["objectId", "objectType", "_org"]
+}`
+
+// TestRoutesAreDocumented fails when a route is registered without an entry in
+// the OpenAPI operation table, or when the document references a missing component.
+func TestRoutesAreDocumented(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Setting up routes does not connect to Redis
+	redisClient := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	schemaRegistry, err := schemas.NewRegistry(redisClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := resources.NewRegistry()
+	if _, err := registry.Register("policy", []byte(policySchema)); err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	SetupRoutes(router, redisClient, &elastic.Factory{}, registry, schemaRegistry, auth.Chain{},
+		tenancy.NewResolver(tenancy.NewStore(redisClient), nil, nil), ratelimit.Policies{},
+		idempotency.NewStore(redisClient, time.Hour), graphqlapi.Limits{MaxDepth: 8, MaxComplexity: 2000}, health.NewChecker(),
+		services.NewChangeFeedService(changefeed.NewFeed(redisClient), 1, nil))
+
+	doc, undocumented := openapi.Build(router.Routes(), map[string][]byte{}, registry.All())
+	for _, route := range undocumented {
+		t.Errorf("route %s has no OpenAPI operation; add one to internal/openapi/operations.go", route)
+	}
+
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, match := range regexp.MustCompile(`"\$ref":"#/components/(schemas|responses)/([^"]+)"`).FindAllStringSubmatch(string(encoded), -1) {
+		kind, name := match[1], match[2]
+		_, found := doc.Components.Schemas[name]
+		if kind == "responses" {
+			_, found = doc.Components.Responses[name]
+		}
+		if !found {
+			t.Errorf("OpenAPI document references missing component %s/%s", kind, name)
+		}
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.1") {
+		t.Errorf("expected an OpenAPI 3.1 document, got %s", doc.OpenAPI)
+	}
+}
+
+// TestDedicatedPathsAreReserved fails when a route outside the resource routes
+// uses a top-level /api/v1 segment that resources may still claim, which would
+// make registering such a resource panic on the duplicate route.
+func TestDedicatedPathsAreReserved(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	redisClient := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	schemaRegistry, err := schemas.NewRegistry(redisClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	SetupRoutes(router, redisClient, &elastic.Factory{}, resources.NewRegistry(), schemaRegistry, auth.Chain{},
+		tenancy.NewResolver(tenancy.NewStore(redisClient), nil, nil), ratelimit.Policies{},
+		idempotency.NewStore(redisClient, time.Hour), graphqlapi.Limits{MaxDepth: 8, MaxComplexity: 2000}, health.NewChecker(),
+		services.NewChangeFeedService(changefeed.NewFeed(redisClient), 1, nil))
+
+	for _, route := range router.Routes() {
+		path, ok := strings.CutPrefix(route.Path, "/api/v1/")
+		if !ok {
+			continue
+		}
+		segment, _, _ := strings.Cut(path, "/")
+		if !resources.Reserved(segment) {
+			t.Errorf("route %s %s uses /api/v1/%s, which is not reserved in internal/resources/registry.go", route.Method, route.Path, segment)
+		}
+	}
+}
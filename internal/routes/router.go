@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"net/http"
+
+	"BigDataForge/internal/middlewares"
+	"BigDataForge/internal/problems"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewRouter creates the Gin router of the API. Client IPs, which key the
+// pre-auth rate limit and are recorded in the audit log, are only taken from
+// X-Forwarded-For or X-Real-IP when the request comes from one of the trusted
+// proxies (IPs or CIDRs); otherwise the peer address is used. Panics are
+// reported as problem documents.
+func NewRouter(trustedProxies []string) (*gin.Engine, error) {
+	router := gin.New()
+	if len(trustedProxies) == 0 {
+		trustedProxies = nil
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+	router.Use(middlewares.LoggingMiddleware(), gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Internal server error")
+	}))
+	return router, nil
+}
@@ -0,0 +1,64 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"BigDataForge/internal/middlewares"
+	"BigDataForge/internal/ratelimit"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+// TestIPRateLimitIgnoresForgedForwardedFor fails when a client can pick the
+// key of the pre-auth IP limit through X-Forwarded-For.
+func TestIPRateLimitIgnoresForgedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+	limiter := ratelimit.NewLimiter(redisClient)
+	policy := ratelimit.Policy{Name: "ip", Limit: 1, Period: time.Minute}
+
+	for _, tc := range []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		want           []int
+	}{
+		{"no trusted proxies", nil, "192.0.2.1:1234", []int{http.StatusOK, http.StatusTooManyRequests}},
+		{"untrusted peer", []string{"10.0.0.0/8"}, "192.0.2.2:1234", []int{http.StatusOK, http.StatusTooManyRequests}},
+		{"trusted proxy", []string{"10.0.0.0/8"}, "10.0.0.1:1234", []int{http.StatusOK, http.StatusOK}},
+	} {
+		server.FlushAll()
+		router, err := NewRouter(tc.trustedProxies)
+		if err != nil {
+			t.Fatal(err)
+		}
+		router.GET("/api/v1/plans", middlewares.IPRateLimitMiddleware(limiter, policy), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		// Each request claims to come from another client
+		for i, forwardedFor := range []string{"198.51.100.1", "198.51.100.2"} {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/plans", nil)
+			req.RemoteAddr = tc.remoteAddr
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			if recorder.Code != tc.want[i] {
+				t.Errorf("%s: request %d = %d, want %d", tc.name, i+1, recorder.Code, tc.want[i])
+			}
+		}
+	}
+}
+
+func TestNewRouterRejectsInvalidProxy(t *testing.T) {
+	if _, err := NewRouter([]string{"not-an-ip"}); err == nil {
+		t.Error("NewRouter accepted an invalid trusted proxy")
+	}
+}
@@ -0,0 +1,204 @@
+package schemas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+var (
+	ErrUnknownSchema  = errors.New("unknown schema")
+	ErrUnknownVersion = errors.New("unknown schema version")
+)
+
+// Redis layout per schema name:
+//
+//	schema:<name>:versions  hash of version -> schema JSON (uploaded versions only)
+//	schema:<name>:seq       counter used to number uploaded versions
+//	schema:<name>:active    the version used for validation, DefaultVersion when unset
+
+// Version describes one stored version of a schema.
+type Version struct {
+	Version string `json:"version"`
+	Active  bool   `json:"active"`
+	Builtin bool   `json:"builtin"`
+}
+
+// Registry serves versioned JSON schemas. Compiled schemas are cached in
+// memory; only the active version pointer is read from Redis per request.
+type Registry struct {
+	redisClient *redis.Client
+
+	mu       sync.RWMutex
+	compiled map[string]*gojsonschema.Schema
+}
+
+// NewRegistry compiles the embedded default schemas and returns a registry
+// backed by Redis for uploaded versions.
+func NewRegistry(redisClient *redis.Client) (*Registry, error) {
+	registry := &Registry{
+		redisClient: redisClient,
+		compiled:    make(map[string]*gojsonschema.Schema),
+	}
+	for name, raw := range defaults {
+		schema, err := compile(raw)
+		if err != nil {
+			return nil, fmt.Errorf("embedded schema %s: %w", name, err)
+		}
+		registry.compiled[cacheKey(name, DefaultVersion)] = schema
+	}
+	return registry, nil
+}
+
+func cacheKey(name, version string) string {
+	return name + "@" + version
+}
+
+func compile(raw []byte) (*gojsonschema.Schema, error) {
+	return gojsonschema.NewSchema(gojsonschema.NewBytesLoader(raw))
+}
+
+// Active returns the compiled active schema and its version.
+func (r *Registry) Active(ctx context.Context, name string) (*gojsonschema.Schema, string, error) {
+	version, err := r.ActiveVersion(ctx, name)
+	if err != nil {
+		return nil, "", err
+	}
+	schema, err := r.Compiled(ctx, name, version)
+	return schema, version, err
+}
+
+// ActiveVersion returns the version currently used to validate documents.
+func (r *Registry) ActiveVersion(ctx context.Context, name string) (string, error) {
+	if _, ok := defaults[name]; !ok {
+		return "", ErrUnknownSchema
+	}
+	version, err := r.redisClient.Get(ctx, "schema:"+name+":active").Result()
+	if err == redis.Nil {
+		return DefaultVersion, nil
+	}
+	return version, err
+}
+
+// Compiled returns a compiled schema version, compiling and caching it on first use.
+func (r *Registry) Compiled(ctx context.Context, name, version string) (*gojsonschema.Schema, error) {
+	r.mu.RLock()
+	schema, ok := r.compiled[cacheKey(name, version)]
+	r.mu.RUnlock()
+	if ok {
+		return schema, nil
+	}
+
+	raw, err := r.Raw(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+	schema, err = compile(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.compiled[cacheKey(name, version)] = schema
+	r.mu.Unlock()
+	return schema, nil
+}
+
+// Raw returns the schema document of a version.
+func (r *Registry) Raw(ctx context.Context, name, version string) ([]byte, error) {
+	builtin, ok := defaults[name]
+	if !ok {
+		return nil, ErrUnknownSchema
+	}
+	if version == DefaultVersion {
+		return builtin, nil
+	}
+
+	raw, err := r.redisClient.HGet(ctx, "schema:"+name+":versions", version).Bytes()
+	if err == redis.Nil {
+		return nil, ErrUnknownVersion
+	}
+	return raw, err
+}
+
+// Upload stores a new version of a schema after checking that it compiles.
+// The new version is not activated.
+func (r *Registry) Upload(ctx context.Context, name string, raw []byte) (string, error) {
+	if _, ok := defaults[name]; !ok {
+		return "", ErrUnknownSchema
+	}
+	schema, err := compile(raw)
+	if err != nil {
+		return "", err
+	}
+
+	seq, err := r.redisClient.Incr(ctx, "schema:"+name+":seq").Result()
+	if err != nil {
+		return "", err
+	}
+	// Uploaded versions start after the embedded one
+	version := strconv.FormatInt(seq+1, 10)
+	if err := r.redisClient.HSet(ctx, "schema:"+name+":versions", version, raw).Err(); err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	r.compiled[cacheKey(name, version)] = schema
+	r.mu.Unlock()
+	return version, nil
+}
+
+// Activate makes a stored version the one used for validation.
+func (r *Registry) Activate(ctx context.Context, name, version string) error {
+	if _, err := r.Compiled(ctx, name, version); err != nil {
+		return err
+	}
+	return r.redisClient.Set(ctx, "schema:"+name+":active", version, 0).Err()
+}
+
+// List returns every version of a schema, oldest first.
+func (r *Registry) List(ctx context.Context, name string) ([]Version, error) {
+	active, err := r.ActiveVersion(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := r.redisClient.HKeys(ctx, "schema:"+name+":versions").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	numbers := []int{1}
+	for _, version := range stored {
+		if n, err := strconv.Atoi(version); err == nil {
+			numbers = append(numbers, n)
+		}
+	}
+	sort.Ints(numbers)
+
+	versions := make([]Version, 0, len(numbers))
+	for _, n := range numbers {
+		version := strconv.Itoa(n)
+		versions = append(versions, Version{
+			Version: version,
+			Active:  version == active,
+			Builtin: version == DefaultVersion,
+		})
+	}
+	return versions, nil
+}
+
+// Names returns the schema names known to the registry.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(defaults))
+	for name := range defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,26 @@
+package schemas
+
+import (
+	_ "embed"
+)
+
+const (
+	PlanSchema      = "plan"
+	PatchPlanSchema = "patch_plan"
+
+	// DefaultVersion is the version of the schemas embedded in the binary.
+	DefaultVersion = "1"
+)
+
+//go:embed plan_schema.json
+var planSchema []byte
+
+//go:embed patch_plan_schema.json
+var patchPlanSchema []byte
+
+// defaults are the embedded schemas served as version 1 of each name. Only
+// these names accept uploaded versions.
+var defaults = map[string][]byte{
+	PlanSchema:      planSchema,
+	PatchPlanSchema: patchPlanSchema,
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"BigDataForge/internal/audit"
+	"BigDataForge/internal/problems"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// AuditService exposes the plan audit log.
+type AuditService struct {
+	auditLog *audit.Log
+}
+
+func NewAuditService(auditLog *audit.Log) *AuditService {
+	return &AuditService{auditLog: auditLog}
+}
+
+// Helper to read the planId, actor, from, to and after query parameters
+func auditFilter(c *gin.Context) (audit.Filter, bool) {
+	filter := audit.Filter{
+		PlanID: c.Query("planId"),
+		Actor:  c.Query("actor"),
+		After:  c.Query("after"),
+	}
+	for name, target := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Query parameter "+name+" must be an RFC 3339 timestamp")
+			return filter, false
+		}
+		*target = parsed
+	}
+	return filter, true
+}
+
+// QueryAuditLog returns a page of audit entries, oldest first
+func (service *AuditService) QueryAuditLog(c *gin.Context) {
+	filter, ok := auditFilter(c)
+	if !ok {
+		return
+	}
+
+	limit := defaultAuditLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxAuditLimit {
+			problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Query parameter limit must be between 1 and 1000")
+			return
+		}
+		limit = parsed
+	}
+
+	entries, err := service.auditLog.Query(c.Request.Context(), filter, limit)
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to read audit log")
+		return
+	}
+
+	// A full page may be followed by more entries; pass next as "after" to continue
+	response := gin.H{"entries": entries}
+	if len(entries) == limit {
+		response["next"] = entries[len(entries)-1].ID
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ExportAuditLog streams every matching entry as newline-delimited JSON
+func (service *AuditService) ExportAuditLog(c *gin.Context) {
+	filter, ok := auditFilter(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", `attachment; filename="plan-audit.ndjson"`)
+	c.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(c.Writer)
+	err := service.auditLog.Scan(c.Request.Context(), filter, func(entry audit.Entry) bool {
+		return encoder.Encode(entry) == nil
+	})
+	if err != nil {
+		// Headers are already sent, so the truncated export can only be logged
+		slog.ErrorContext(c.Request.Context(), "Audit log export failed", "error", err)
+	}
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"BigDataForge/internal/changefeed"
+	"BigDataForge/internal/lifecycle"
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/tenancy"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	changeFeedBlock     = 15 * time.Second // longest wait for new events before a keep-alive
+	changeFeedBatchSize = 100
+	// WebSocket close code of streams whose subscriber fell behind the trimmed feed
+	closeEventsExpired = 4410
+)
+
+var streamIDPattern = regexp.MustCompile(`^\d+(-\d+)?$`)
+
+// ChangeFeedService streams plan changes to subscribers. Each subscriber
+// blocks a connection of the feed's Redis client while it waits for events, so
+// the service is shared by the REST and gRPC APIs and admits at most
+// maxSubscribers at once, the size of that client's pool.
+type ChangeFeedService struct {
+	feed     *changefeed.Feed
+	slots    chan struct{}
+	upgrader websocket.Upgrader
+}
+
+// NewChangeFeedService accepts WebSocket upgrades from pages of the API's own
+// origin and of allowedOrigins. Clients outside a browser send no Origin and
+// are always accepted.
+func NewChangeFeedService(feed *changefeed.Feed, maxSubscribers int, allowedOrigins []string) *ChangeFeedService {
+	return &ChangeFeedService{
+		feed:     feed,
+		slots:    make(chan struct{}, maxSubscribers),
+		upgrader: websocket.Upgrader{CheckOrigin: checkOrigin(allowedOrigins)},
+	}
+}
+
+// Helper to accept WebSocket upgrades without an Origin, from the API's own
+// origin or from an allowed one, so other sites cannot open streams
+func checkOrigin(allowedOrigins []string) func(r *http.Request) bool {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[strings.ToLower(strings.TrimSuffix(origin, "/"))] = true
+	}
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		parsed, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+		return strings.EqualFold(parsed.Host, r.Host) || allowed[strings.ToLower(origin)]
+	}
+}
+
+// Helper to take a subscriber slot, returning the function that gives it back
+func (service *ChangeFeedService) acquire() (func(), error) {
+	select {
+	case service.slots <- struct{}{}:
+		return func() { <-service.slots }, nil
+	default:
+		return nil, problems.NewError(http.StatusServiceUnavailable, problems.CodeUnavailable, "Too many change feed subscribers")
+	}
+}
+
+// changeSubscription is what one client asked to receive.
+type changeSubscription struct {
+	tenant          *tenancy.Tenant
+	org             string
+	includeDocument bool
+	lastID          string
+}
+
+// Helper to check whether a subscriber may and wants to see an event
+func (sub *changeSubscription) wants(event changefeed.Event) bool {
+	return (sub.org == "" || event.Org == sub.org) && sub.tenant.Can(event.Org, tenancy.RoleViewer)
+}
+
+// WatchRequest selects the plan changes a subscriber receives.
+type WatchRequest struct {
+	Org             string
+	IncludeDocument bool
+	LastEventID     string // resume after this event; only new events when empty
+}
+
+// Helper to check a watch request against the caller's memberships and find where it starts
+func (service *ChangeFeedService) subscribe(ctx context.Context, req WatchRequest) (*changeSubscription, error) {
+	sub := &changeSubscription{
+		tenant:          tenancy.FromContext(ctx),
+		org:             req.Org,
+		includeDocument: req.IncludeDocument,
+		lastID:          req.LastEventID,
+	}
+	if sub.org != "" && !sub.tenant.Can(sub.org, tenancy.RoleViewer) {
+		return nil, problems.NewError(http.StatusForbidden, problems.CodeForbidden, fmt.Sprintf("Requires the viewer role in organization %q", sub.org))
+	}
+
+	if sub.lastID != "" && !streamIDPattern.MatchString(sub.lastID) {
+		return nil, problems.NewError(http.StatusBadRequest, problems.CodeInvalidRequest, "Last-Event-ID is not a change event ID")
+	}
+	if sub.lastID != "" {
+		expired, err := service.feed.Expired(ctx, sub.lastID)
+		if err != nil {
+			return nil, problems.NewError(http.StatusInternalServerError, problems.CodeInternal, "Failed to read change feed")
+		}
+		if expired {
+			return nil, eventsExpired()
+		}
+	}
+	if sub.lastID == "" {
+		latest, err := service.feed.LatestID(ctx)
+		if err != nil {
+			return nil, problems.NewError(http.StatusInternalServerError, problems.CodeInternal, "Failed to read change feed")
+		}
+		sub.lastID = latest
+	}
+	return sub, nil
+}
+
+// Helper to build the problem of a resume point older than the trimmed feed
+func eventsExpired() error {
+	return problems.NewError(http.StatusGone, problems.CodeEventsExpired, "Change events after Last-Event-ID were trimmed; reload the plans and subscribe again without it")
+}
+
+// Watch sends the plan changes selected by req until ctx ends or send fails.
+// Errors about the request itself are returned before anything is sent; the
+// events_expired problem is also returned once the subscriber falls behind the
+// trimmed feed.
+func (service *ChangeFeedService) Watch(ctx context.Context, req WatchRequest, send func(changefeed.Event) error) error {
+	release, err := service.acquire()
+	if err != nil {
+		return err
+	}
+	defer release()
+	sub, err := service.subscribe(ctx, req)
+	if err != nil {
+		return err
+	}
+	return service.follow(ctx, sub, send, func() error { return nil })
+}
+
+// StreamChanges streams plan changes over Server-Sent Events, or over a
+// WebSocket when the request asks for an upgrade. Streams resume after the
+// Last-Event-ID header or lastEventId query parameter.
+func (service *ChangeFeedService) StreamChanges(c *gin.Context) {
+	req := WatchRequest{
+		Org:             c.Query("org"),
+		IncludeDocument: c.Query("include") == "document",
+		LastEventID:     c.GetHeader("Last-Event-ID"),
+	}
+	if req.LastEventID == "" {
+		req.LastEventID = c.Query("lastEventId")
+	}
+
+	// The slot covers the connection subscribe reads the latest event ID on
+	release, err := service.acquire()
+	if err != nil {
+		c.Header("Retry-After", strconv.Itoa(int(changeFeedBlock.Seconds())))
+		problems.AbortWithError(c, err)
+		return
+	}
+	defer release()
+	sub, err := service.subscribe(RequestContext(c), req)
+	if err != nil {
+		problems.AbortWithError(c, err)
+		return
+	}
+
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		service.streamWebSocket(c, sub)
+		return
+	}
+	service.streamSSE(c, sub)
+}
+
+// Helper to follow the feed until ctx ends or send fails. idle is called
+// whenever no event arrived within changeFeedBlock. It returns the
+// events_expired problem when events the subscriber had not read yet were
+// trimmed, and nil otherwise.
+func (service *ChangeFeedService) follow(ctx context.Context, sub *changeSubscription, send func(changefeed.Event) error, idle func() error) error {
+	for ctx.Err() == nil {
+		events, err := service.feed.Read(ctx, sub.lastID, changeFeedBatchSize, changeFeedBlock)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.ErrorContext(ctx, "Failed to read change feed", "error", err)
+			}
+			return nil
+		}
+		if len(events) == 0 {
+			if err := idle(); err != nil {
+				return nil
+			}
+			continue
+		}
+
+		// A slow subscriber may fall behind the events the relay trims
+		expired, err := service.feed.Expired(ctx, sub.lastID)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to read change feed", "error", err)
+			return nil
+		}
+		if expired {
+			return eventsExpired()
+		}
+
+		for _, event := range events {
+			sub.lastID = event.ID
+			if !sub.wants(event) {
+				continue
+			}
+			if !sub.includeDocument {
+				event.Document = nil
+			}
+			if err := send(event); err != nil {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func (service *ChangeFeedService) streamSSE(c *gin.Context, sub *changeSubscription) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	fmt.Fprint(c.Writer, "retry: 3000\n\n")
+	c.Writer.Flush()
+
+	// Clients reconnect with Last-Event-ID, so streams end when the server shuts down
+	ctx, cancel := lifecycle.UntilShutdown(c.Request.Context())
+	defer cancel()
+
+	err := service.follow(ctx, sub, func(event changefeed.Event) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	}, func() error {
+		if _, err := fmt.Fprint(c.Writer, ": keep-alive\n\n"); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+
+	// The status is already sent, so the problem goes out as a reset event
+	var problemErr *problems.Error
+	if errors.As(err, &problemErr) {
+		data, _ := json.Marshal(problems.New(c, problemErr.Status, problemErr.Code, problemErr.Detail))
+		fmt.Fprintf(c.Writer, "event: reset\ndata: %s\n\n", data)
+		c.Writer.Flush()
+	}
+}
+
+func (service *ChangeFeedService) streamWebSocket(c *gin.Context, sub *changeSubscription) {
+	conn, err := service.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader already wrote an error response
+		return
+	}
+	defer conn.Close()
+
+	// The feed is one-way; reading only serves to notice when the client goes away
+	ctx, cancel := lifecycle.UntilShutdown(c.Request.Context())
+	defer cancel()
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	err = service.follow(ctx, sub, func(event changefeed.Event) error {
+		return conn.WriteJSON(event)
+	}, func() error {
+		return conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second))
+	})
+	if err != nil {
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(closeEventsExpired, problems.CodeEventsExpired), time.Now().Add(10*time.Second))
+	}
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"BigDataForge/internal/changefeed"
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/tenancy"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/gorilla/websocket"
+)
+
+func TestCheckOrigin(t *testing.T) {
+	check := checkOrigin([]string{"https://app.example.com", "http://localhost:3000/"})
+
+	for _, tc := range []struct {
+		origin  string
+		allowed bool
+	}{
+		{"", true}, // not a browser
+		{"https://api.example.com", true},
+		{"https://app.example.com", true},
+		{"HTTPS://APP.EXAMPLE.COM", true},
+		{"http://localhost:3000", true},
+		{"https://evil.example.com", false},
+		{"http://app.example.com", false},
+		{"https://app.example.com:8443", false},
+		{"null", false},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "https://api.example.com/api/v1/plans/changes", nil)
+		if tc.origin != "" {
+			r.Header.Set("Origin", tc.origin)
+		}
+		if got := check(r); got != tc.allowed {
+			t.Errorf("origin %q allowed = %v, want %v", tc.origin, got, tc.allowed)
+		}
+	}
+}
+
+func TestStreamChangesRefusesForeignOrigins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+	service := NewChangeFeedService(changefeed.NewFeed(redisClient), 1, []string{"https://app.example.com"})
+
+	router := gin.New()
+	router.GET("/api/v1/plans/changes", func(c *gin.Context) {
+		c.Set(tenancy.TenantKey, &tenancy.Tenant{Subject: "alice", Memberships: map[string]tenancy.Role{"a.com": tenancy.RoleViewer}})
+		service.StreamChanges(c)
+	})
+	api := httptest.NewServer(router)
+	t.Cleanup(api.Close)
+
+	header := http.Header{"Origin": {"https://evil.example.com"}}
+	conn, response, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(api.URL, "http")+"/api/v1/plans/changes", header)
+	if err == nil {
+		conn.Close()
+		t.Fatal("a page of another site opened the change feed")
+	}
+	if response == nil || response.StatusCode != http.StatusForbidden {
+		t.Errorf("upgrade response = %v, want 403", response)
+	}
+}
+
+// Helper to set up a feed holding events 6-0 to 8-0 of a.com, after a trim
+// that dropped every event up to 5-0
+func newTrimmedFeed(t *testing.T) *changefeed.Feed {
+	t.Helper()
+	server := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+	for _, id := range []string{"6-0", "7-0", "8-0"} {
+		if _, err := server.XAdd("changes:plans", id, []string{"type", "create", "planId", "p" + id, "org", "a.com"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Where Feed.Trim records the newest trimmed event
+	server.Set("changes:plans:trimmed", "5-0")
+	return changefeed.NewFeed(redisClient)
+}
+
+func TestStreamChangesRefusesExpiredLastEventID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := NewChangeFeedService(newTrimmedFeed(t), 1, nil)
+	router := gin.New()
+	router.GET("/api/v1/plans/changes", func(c *gin.Context) {
+		c.Set(tenancy.TenantKey, &tenancy.Tenant{Subject: "alice", Memberships: map[string]tenancy.Role{"a.com": tenancy.RoleViewer}})
+		service.StreamChanges(c)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/plans/changes", nil)
+	req.Header.Set("Last-Event-ID", "4-0")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	var problem problems.Problem
+	if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil || recorder.Code != http.StatusGone || problem.Code != problems.CodeEventsExpired {
+		t.Errorf("response = %d %s, want 410 %s", recorder.Code, recorder.Body, problems.CodeEventsExpired)
+	}
+}
+
+func TestFollowStopsWhenEventsExpired(t *testing.T) {
+	service := NewChangeFeedService(newTrimmedFeed(t), 1, nil)
+	tenant := &tenancy.Tenant{Subject: "alice", Memberships: map[string]tenancy.Role{"a.com": tenancy.RoleViewer}}
+	errStop := errors.New("stop")
+
+	for _, tc := range []struct {
+		lastID   string
+		wantSent []string
+		expired  bool
+	}{
+		{"4-0", nil, true}, // 5-0 is gone
+		{"5-0", []string{"6-0"}, false},
+		{"7-0", []string{"8-0"}, false},
+	} {
+		var sent []string
+		sub := &changeSubscription{tenant: tenant, lastID: tc.lastID}
+		err := service.follow(context.Background(), sub, func(event changefeed.Event) error {
+			sent = append(sent, event.ID)
+			return errStop
+		}, func() error { return errStop })
+
+		var problem *problems.Error
+		if expired := errors.As(err, &problem) && problem.Code == problems.CodeEventsExpired; expired != tc.expired {
+			t.Errorf("follow from %s = %v, want expired %v", tc.lastID, err, tc.expired)
+		}
+		if strings.Join(sent, ",") != strings.Join(tc.wantSent, ",") {
+			t.Errorf("follow from %s sent %v, want %v", tc.lastID, sent, tc.wantSent)
+		}
+	}
+}
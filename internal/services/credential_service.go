@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+
+	"BigDataForge/internal/auth"
+	"BigDataForge/internal/problems"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CredentialService manages machine credentials: API keys and HMAC signing keys.
+type CredentialService struct {
+	apiKeys  *auth.APIKeyStore
+	hmacKeys *auth.HMACKeyStore
+}
+
+func NewCredentialService(apiKeys *auth.APIKeyStore, hmacKeys *auth.HMACKeyStore) *CredentialService {
+	return &CredentialService{apiKeys: apiKeys, hmacKeys: hmacKeys}
+}
+
+type credentialRequest struct {
+	Subject string   `json:"subject" binding:"required"`
+	Scopes  []string `json:"scopes"`
+}
+
+// CreateAPIKey issues a new API key; the key is only returned in this response
+func (service *CredentialService) CreateAPIKey(c *gin.Context) {
+	var req credentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Invalid data")
+		return
+	}
+
+	record, key, err := service.apiKeys.Create(c.Request.Context(), req.Subject, req.Scopes)
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to create API key")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"apiKey": record, "key": key})
+}
+
+// ListAPIKeys lists API keys without their secrets
+func (service *CredentialService) ListAPIKeys(c *gin.Context) {
+	keys, err := service.apiKeys.List(c.Request.Context())
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to list API keys")
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// RevokeAPIKey deletes an API key by ID
+func (service *CredentialService) RevokeAPIKey(c *gin.Context) {
+	err := service.apiKeys.Revoke(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, auth.ErrUnknownAPIKey) {
+		problems.Abort(c, http.StatusNotFound, problems.CodeNotFound, "API key not found")
+		return
+	}
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to revoke API key")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// CreateHMACKey issues a request signing key; the secret is only returned in this response
+func (service *CredentialService) CreateHMACKey(c *gin.Context) {
+	var req credentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Invalid data")
+		return
+	}
+
+	key, secret, err := service.hmacKeys.Create(c.Request.Context(), req.Subject, req.Scopes)
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to create HMAC key")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"hmacKey": key, "secret": secret})
+}
+
+// RevokeHMACKey deletes a request signing key
+func (service *CredentialService) RevokeHMACKey(c *gin.Context) {
+	err := service.hmacKeys.Revoke(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, auth.ErrUnknownHMACKey) {
+		problems.Abort(c, http.StatusNotFound, problems.CodeNotFound, "HMAC key not found")
+		return
+	}
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to revoke HMAC key")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"net/http"
+
+	"BigDataForge/internal/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthService answers liveness and readiness probes and reports the state
+// of every dependency to operators.
+type HealthService struct {
+	checker *health.Checker
+}
+
+func NewHealthService(checker *health.Checker) *HealthService {
+	return &HealthService{checker: checker}
+}
+
+// Live reports that the process is serving requests; it checks no dependency
+func (service *HealthService) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+// Ready runs the checks and answers 503 when a critical one fails. The
+// response only carries check statuses, as probes are unauthenticated.
+func (service *HealthService) Ready(c *gin.Context) {
+	report := service.checker.Run(c.Request.Context())
+	c.JSON(readyStatus(report), report.Summary())
+}
+
+// Status runs the checks and returns their errors and details
+func (service *HealthService) Status(c *gin.Context) {
+	report := service.checker.Run(c.Request.Context())
+	c.JSON(readyStatus(report), report)
+}
+
+// Helper to map a report to the HTTP status of a probe
+func readyStatus(report health.Report) int {
+	if report.Ready() {
+		return http.StatusOK
+	}
+	return http.StatusServiceUnavailable
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/tenancy"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MembershipService manages the org memberships granted through the admin API.
+type MembershipService struct {
+	store *tenancy.Store
+}
+
+func NewMembershipService(store *tenancy.Store) *MembershipService {
+	return &MembershipService{store: store}
+}
+
+type membershipRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// GetMemberships lists the stored memberships of a subject
+func (service *MembershipService) GetMemberships(c *gin.Context) {
+	memberships, err := service.store.Memberships(c.Request.Context(), c.Param("subject"))
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to load memberships")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"subject": c.Param("subject"), "memberships": memberships})
+}
+
+// GrantMembership sets the role of a subject in an org
+func (service *MembershipService) GrantMembership(c *gin.Context) {
+	var req membershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Invalid data")
+		return
+	}
+	role, ok := tenancy.ParseRole(req.Role)
+	if !ok {
+		problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Role must be viewer, editor or admin")
+		return
+	}
+
+	if err := service.store.Grant(c.Request.Context(), c.Param("subject"), c.Param("org"), role); err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to grant membership")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"subject": c.Param("subject"), "org": c.Param("org"), "role": role})
+}
+
+// RevokeMembership removes a subject from an org
+func (service *MembershipService) RevokeMembership(c *gin.Context) {
+	err := service.store.Revoke(c.Request.Context(), c.Param("subject"), c.Param("org"))
+	if errors.Is(err, tenancy.ErrUnknownMembership) {
+		problems.Abort(c, http.StatusNotFound, problems.CodeNotFound, "Membership not found")
+		return
+	}
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to revoke membership")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
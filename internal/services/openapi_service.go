@@ -0,0 +1,50 @@
+package services
+
+import (
+	"net/http"
+
+	"BigDataForge/internal/openapi"
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/resources"
+	"BigDataForge/internal/schemas"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPIService serves the OpenAPI document of the router's routes. The
+// document is generated per request so it describes the active schema versions.
+type OpenAPIService struct {
+	router    *gin.Engine
+	schemas   *schemas.Registry
+	resources *resources.Registry
+}
+
+func NewOpenAPIService(router *gin.Engine, schemaRegistry *schemas.Registry, registry *resources.Registry) *OpenAPIService {
+	return &OpenAPIService{router: router, schemas: schemaRegistry, resources: registry}
+}
+
+// GetDocument returns the OpenAPI document
+func (service *OpenAPIService) GetDocument(c *gin.Context) {
+	planSchemas := make(map[string][]byte)
+	for _, name := range service.schemas.Names() {
+		version, err := service.schemas.ActiveVersion(c.Request.Context(), name)
+		if err != nil {
+			problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to read active schema version")
+			return
+		}
+		raw, err := service.schemas.Raw(c.Request.Context(), name, version)
+		if err != nil {
+			problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to read schema")
+			return
+		}
+		planSchemas[name] = raw
+	}
+
+	doc, _ := openapi.Build(service.router.Routes(), planSchemas, service.resources.All())
+	c.JSON(http.StatusOK, doc)
+}
+
+// GetDocs returns the API reference page rendering the document
+func (service *OpenAPIService) GetDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", openapi.DocsPage)
+}
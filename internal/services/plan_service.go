@@ -6,12 +6,23 @@ import (
 	"crypto/sha1"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
 
+	"BigDataForge/internal/audit"
+	"BigDataForge/internal/auth"
+	"BigDataForge/internal/changefeed"
 	"BigDataForge/internal/elastic"
+	"BigDataForge/internal/logging"
 	"BigDataForge/internal/models"
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/requestid"
+	"BigDataForge/internal/tenancy"
+	"BigDataForge/internal/validators"
 
 	"github.com/elastic/go-elasticsearch/esapi"
 	"github.com/gin-gonic/gin"
@@ -23,12 +34,18 @@ var ctx = context.Background()
 type PlanService struct {
 	redisClient *redis.Client
 	esClient    *elastic.Factory
+	auditLog    *audit.Log
+	changes     *changefeed.Feed
+	rules       *validators.RuleEngine
 }
 
 func NewPlanService(redisClient *redis.Client, esFactory *elastic.Factory) *PlanService {
 	return &PlanService{
 		redisClient: redisClient,
 		esClient:    esFactory,
+		auditLog:    audit.NewLog(redisClient),
+		changes:     changefeed.NewFeed(redisClient),
+		rules:       validators.NewRuleEngine(validators.DefaultPlanRules()...),
 	}
 }
 
@@ -40,8 +57,40 @@ func generateETag(plan models.Plan) string {
 	return hex.EncodeToString(hash.Sum(nil))
 }
 
+// Helper function to check the caller's role in an org. Callers that cannot
+// even view the org get notFound (when set) so object IDs of other orgs don't leak.
+func checkOrg(ctx context.Context, org string, required tenancy.Role, notFound string) error {
+	tenant := tenancy.FromContext(ctx)
+	if tenant.Can(org, required) {
+		return nil
+	}
+	if notFound != "" && !tenant.Can(org, tenancy.RoleViewer) {
+		return problems.NewError(http.StatusNotFound, problems.CodeNotFound, notFound)
+	}
+	return problems.NewError(http.StatusForbidden, problems.CodeForbidden, fmt.Sprintf("Requires the %s role in organization %q", required, org))
+}
+
+// Helper function to check the caller's role in an org from a gin handler
+func authorizeOrg(c *gin.Context, org string, required tenancy.Role, notFound string) bool {
+	if err := checkOrg(c, org, required, notFound); err != nil {
+		problems.AbortWithError(c, err)
+		return false
+	}
+	return true
+}
+
+// RequestContext carries the caller of a gin request into the context-based plan operations.
+func RequestContext(c *gin.Context) context.Context {
+	ctx := c.Request.Context()
+	if principal := auth.FromContext(c); principal != nil {
+		ctx = auth.NewContext(ctx, principal)
+	}
+	ctx = tenancy.NewContext(ctx, tenancy.FromContext(c))
+	return audit.NewContext(ctx, audit.Origin{RequestID: requestid.Get(c), ClientIP: c.ClientIP()})
+}
+
 // Helper function to check if a plan exists in Redis
-func (service *PlanService) getPlanFromRedis(planID string) (*models.Plan, error) {
+func (service *PlanService) getPlanFromRedis(ctx context.Context, planID string) (*models.Plan, error) {
 	planJSON, err := service.redisClient.Get(ctx, "plan:"+planID).Result()
 	if err == redis.Nil {
 		return nil, nil // Plan does not exist
@@ -58,122 +107,337 @@ func (service *PlanService) getPlanFromRedis(planID string) (*models.Plan, error
 	return &plan, nil
 }
 
-// CreatePlan handles creating a new plan
-func (service *PlanService) CreatePlan(c *gin.Context) {
-	var plan models.Plan
-	if err := c.ShouldBindJSON(&plan); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
-		return
+// Helper function to load a plan the caller holds at least the required role for
+func (service *PlanService) getAuthorizedPlan(ctx context.Context, planID string, required tenancy.Role) (*models.Plan, error) {
+	plan, err := service.getPlanFromRedis(ctx, planID)
+	if err != nil {
+		return nil, problems.NewError(http.StatusInternalServerError, problems.CodeInternal, "Failed to retrieve plan")
+	}
+	if plan == nil {
+		return nil, problems.NewError(http.StatusNotFound, problems.CodeNotFound, "Plan not found")
+	}
+	if err := checkOrg(ctx, plan.Org, required, "Plan not found"); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// StoredPlan is a plan together with its ETag and the schema version it was validated against.
+type StoredPlan struct {
+	Plan          *models.Plan
+	ETag          string
+	SchemaVersion string
+}
+
+// Create stores a new plan validated against schemaVersion and returns its ETag.
+func (service *PlanService) Create(ctx context.Context, plan models.Plan, schemaVersion string) (string, error) {
+	if err := checkOrg(ctx, plan.Org, tenancy.RoleEditor, ""); err != nil {
+		return "", err
 	}
 
 	planID := plan.ObjectID
 
 	// Check if the plan already exists
-	existingPlan, err := service.getPlanFromRedis(planID)
+	existingPlan, err := service.getPlanFromRedis(ctx, planID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing data"})
-		return
+		return "", problems.NewError(http.StatusInternalServerError, problems.CodeInternal, "Failed to check existing data")
 	}
 	if existingPlan != nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "Plan already exists"})
-		return
+		return "", problems.NewError(http.StatusConflict, problems.CodeConflict, "Plan already exists")
 	}
 
 	// Save the new plan in Redis
-	if err := service.savePlanToRedis(planID, plan); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store plan"})
-		return
+	eTag := generateETag(plan)
+	entry := audit.FromContext(ctx, audit.ActionCreate, planID)
+	entry.Org, entry.ETagAfter = plan.Org, eTag
+	if err := service.savePlanToRedis(ctx, planID, plan, schemaVersion, entry); err != nil {
+		return "", problems.NewError(http.StatusInternalServerError, problems.CodeInternal, "Failed to store plan")
+	}
+	return eTag, nil
+}
+
+// Get returns a plan the caller may view.
+func (service *PlanService) Get(ctx context.Context, planID string) (*StoredPlan, error) {
+	plan, err := service.getAuthorizedPlan(ctx, planID, tenancy.RoleViewer)
+	if err != nil {
+		return nil, err
 	}
 
-	// Generate and set the ETag
-	c.Header("ETag", generateETag(plan))
-	c.JSON(http.StatusCreated, gin.H{"message": "Plan created", "planId": planID})
+	stored := &StoredPlan{Plan: plan, ETag: generateETag(*plan)}
+	schemaVersion, err := service.redisClient.Get(ctx, "plan:"+planID+":schemaVersion").Result()
+	if err == nil {
+		stored.SchemaVersion = schemaVersion
+	}
+	return stored, nil
 }
 
-// GetPlan retrieves a plan by ID
-func (service *PlanService) GetPlan(c *gin.Context) {
-	planID := c.Query("id")
+// Delete removes a plan the caller may edit.
+func (service *PlanService) Delete(ctx context.Context, planID string) error {
+	existingPlan, err := service.getAuthorizedPlan(ctx, planID, tenancy.RoleEditor)
+	if err != nil {
+		return err
+	}
 
-	plan, err := service.getPlanFromRedis(planID)
+	// Delete the plan and record it in the audit log
+	entry := audit.FromContext(ctx, audit.ActionDelete, planID)
+	entry.Org, entry.ETagBefore = existingPlan.Org, generateETag(*existingPlan)
+	_, err = service.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, "plan:"+planID, "plan:"+planID+":schemaVersion")
+		service.auditLog.Append(ctx, pipe, entry)
+		service.changes.Append(ctx, pipe, changeEvent(entry, nil))
+		return nil
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plan"})
-		return
+		return problems.NewError(http.StatusInternalServerError, problems.CodeInternal, "Failed to delete plan")
 	}
-	if plan == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Plan not found"})
-		return
+	return nil
+}
+
+// Patch merges the fields set in updatedData into a plan and returns its new
+// ETag. A non-empty ifMatch must equal the plan's current ETag.
+func (service *PlanService) Patch(ctx context.Context, planID string, updatedData models.Plan, ifMatch, schemaVersion string) (string, error) {
+	existingPlan, err := service.getAuthorizedPlan(ctx, planID, tenancy.RoleEditor)
+	if err != nil {
+		return "", err
 	}
 
-	// Handle conditional read with ETag
-	eTag := generateETag(*plan)
-	if c.GetHeader("If-None-Match") == eTag {
-		c.Status(http.StatusNotModified)
-		return
+	// Check If-Match for conditional writes
+	eTagBefore := generateETag(*existingPlan)
+	if ifMatch != "" && ifMatch != eTagBefore {
+		return "", problems.NewError(http.StatusPreconditionFailed, problems.CodePreconditionFailed, "Resource has been modified")
 	}
 
-	// Return the plan with ETag
-	c.Header("ETag", eTag)
-	c.JSON(http.StatusOK, plan)
+	// Moving a plan to another org requires the editor role there as well
+	if updatedData.Org != "" {
+		if err := checkOrg(ctx, updatedData.Org, tenancy.RoleEditor, ""); err != nil {
+			return "", err
+		}
+	}
+
+	service.mergePlan(existingPlan, &updatedData)
+
+	// Rules only saw the fields of the patch, so check the merged plan too: a
+	// patch of the root _org alone would leave its children in the old org
+	merged, err := json.Marshal(existingPlan)
+	if err != nil {
+		return "", problems.NewError(http.StatusInternalServerError, problems.CodeInternal, "Failed to update plan")
+	}
+	if err := service.rules.ValidateDocument(merged); err != nil {
+		return "", err
+	}
+
+	// Save the updated plan
+	eTag := generateETag(*existingPlan)
+	entry := audit.FromContext(ctx, audit.ActionPatch, planID)
+	entry.Org, entry.ETagBefore, entry.ETagAfter = existingPlan.Org, eTagBefore, eTag
+	if err := service.savePlanToRedis(ctx, planID, *existingPlan, schemaVersion, entry); err != nil {
+		return "", problems.NewError(http.StatusInternalServerError, problems.CodeInternal, "Failed to update plan")
+	}
+	return eTag, nil
 }
 
-// DeletePlan removes a plan by ID
-func (service *PlanService) DeletePlan(c *gin.Context) {
-	planID := c.Query("id")
+// Update replaces the plan with the same objectId and returns its new ETag.
+func (service *PlanService) Update(ctx context.Context, updatedPlan models.Plan, schemaVersion string) (string, error) {
+	existingPlan, err := service.getAuthorizedPlan(ctx, updatedPlan.ObjectID, tenancy.RoleEditor)
+	if err != nil {
+		return "", err
+	}
+	if err := checkOrg(ctx, updatedPlan.Org, tenancy.RoleEditor, ""); err != nil {
+		return "", err
+	}
+
+	// Delete the existing plan
+	if err := service.DeletePlanByID(updatedPlan.ObjectID); err != nil {
+		return "", problems.NewError(http.StatusInternalServerError, problems.CodeInternal, "Failed to delete existing plan")
+	}
 
-	// Check if the plan exists
-	exists, err := service.redisClient.Exists(ctx, "plan:"+planID).Result()
+	// Create the new plan
+	eTag := generateETag(updatedPlan)
+	entry := audit.FromContext(ctx, audit.ActionUpdate, updatedPlan.ObjectID)
+	entry.Org, entry.ETagBefore, entry.ETagAfter = updatedPlan.Org, generateETag(*existingPlan), eTag
+	if err := service.savePlanToRedis(ctx, updatedPlan.ObjectID, updatedPlan, schemaVersion, entry); err != nil {
+		return "", problems.NewError(http.StatusInternalServerError, problems.CodeInternal, "Failed to create updated plan")
+	}
+	return eTag, nil
+}
+
+// Search runs a match query in Elasticsearch, limited to the orgs the caller
+// can view, and returns the raw search response.
+func (service *PlanService) Search(ctx context.Context, req models.SearchRequest) (map[string]interface{}, error) {
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": map[string]interface{}{
+					"match": map[string]interface{}{
+						req.Key: req.Value,
+					},
+				},
+			},
+		},
+	}
+
+	// Only return documents of orgs the caller can view
+	if orgs, all := tenancy.FromContext(ctx).Orgs(tenancy.RoleViewer); !all {
+		query["query"].(map[string]interface{})["bool"].(map[string]interface{})["filter"] = map[string]interface{}{
+			"terms": map[string]interface{}{
+				"_org.keyword": orgs,
+			},
+		}
+	}
+	queryBytes, err := json.Marshal(query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check plan existence"})
-		return
+		return nil, problems.NewError(http.StatusInternalServerError, problems.CodeInternal, "Failed to build query")
+	}
+	slog.DebugContext(ctx, "Searching plans", "query", logging.Payload(logging.PlanRules, queryBytes))
+
+	client, err := service.esClient.Client()
+	if err != nil {
+		return nil, problems.NewError(http.StatusInternalServerError, problems.CodeInternal, "Failed to create Elasticsearch client")
+	}
+
+	searchReq := esapi.SearchRequest{
+		Index: []string{service.esClient.PlanIndex},
+		Body:  bytes.NewReader(queryBytes),
+	}
+
+	// The search ends with the request, or after the timeout, retries included
+	ctx, cancel := context.WithTimeout(ctx, service.esClient.Timeout)
+	defer cancel()
+	res, err := searchReq.Do(ctx, client)
+	if err != nil {
+		return nil, searchError(ctx, err)
 	}
-	if exists == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Plan not found"})
+	defer res.Body.Close()
+	if res.IsError() {
+		slog.ErrorContext(ctx, "Search failed", "status", res.StatusCode)
+		if res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError {
+			return nil, problems.NewError(http.StatusServiceUnavailable, problems.CodeUnavailable, "Search is temporarily unavailable")
+		}
+		return nil, problems.NewError(http.StatusInternalServerError, problems.CodeInternal, "Failed to execute search")
+	}
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, problems.NewError(http.StatusInternalServerError, problems.CodeInternal, "Failed to parse search results")
+	}
+	return result, nil
+}
+
+// Helper to convert a failed search request into a problem. Searches fail
+// fast while the circuit breaker is open, and time out rather than wait for an
+// unhealthy cluster.
+func searchError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, elastic.ErrUnavailable):
+		return problems.NewError(http.StatusServiceUnavailable, problems.CodeUnavailable, "Search is temporarily unavailable")
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		slog.ErrorContext(ctx, "Search timed out", "error", err)
+		return problems.NewError(http.StatusGatewayTimeout, problems.CodeUnavailable, "Search timed out")
+	case errors.Is(ctx.Err(), context.Canceled):
+		return err // the caller is gone
+	default:
+		slog.ErrorContext(ctx, "Search failed", "error", err)
+		return problems.NewError(http.StatusServiceUnavailable, problems.CodeUnavailable, "Search is temporarily unavailable")
+	}
+}
+
+// SearchHit is one document of a search response: a plan or one of its child objects.
+type SearchHit struct {
+	ID     string
+	Score  float64
+	Source map[string]interface{}
+}
+
+// SearchHits picks the total and the hits out of a raw search response.
+func SearchHits(result map[string]interface{}) (int64, []SearchHit) {
+	var total int64
+	hits, _ := result["hits"].(map[string]interface{})
+	if totalHits, ok := hits["total"].(map[string]interface{}); ok {
+		value, _ := totalHits["value"].(float64)
+		total = int64(value)
+	}
+
+	entries, _ := hits["hits"].([]interface{})
+	searchHits := make([]SearchHit, 0, len(entries))
+	for _, entry := range entries {
+		hit, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var searchHit SearchHit
+		searchHit.ID, _ = hit["_id"].(string)
+		searchHit.Score, _ = hit["_score"].(float64)
+		searchHit.Source, _ = hit["_source"].(map[string]interface{})
+		searchHits = append(searchHits, searchHit)
+	}
+	return total, searchHits
+}
+
+// CreatePlan handles creating a new plan
+func (service *PlanService) CreatePlan(c *gin.Context) {
+	var plan models.Plan
+	if err := c.ShouldBindJSON(&plan); err != nil {
+		problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Invalid data")
 		return
 	}
 
-	// Delete the plan
-	if err := service.redisClient.Del(ctx, "plan:"+planID).Err(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete plan"})
+	eTag, err := service.Create(RequestContext(c), plan, c.GetString(validators.SchemaVersionKey))
+	if err != nil {
+		problems.AbortWithError(c, err)
 		return
 	}
 
-	c.Status(http.StatusNoContent)
+	// Set the ETag
+	c.Header("ETag", eTag)
+	c.JSON(http.StatusCreated, gin.H{"message": "Plan created", "planId": plan.ObjectID})
 }
 
-// PatchPlan updates specific fields of a plan
-func (service *PlanService) PatchPlan(c *gin.Context) {
-	planID := c.Query("id")
-
-	// Get the existing plan
-	existingPlan, err := service.getPlanFromRedis(planID)
+// GetPlan retrieves a plan by ID
+func (service *PlanService) GetPlan(c *gin.Context) {
+	stored, err := service.Get(RequestContext(c), c.Query("id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plan"})
+		problems.AbortWithError(c, err)
 		return
 	}
-	if existingPlan == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Plan not found"})
+
+	// Handle conditional read with ETag
+	if c.GetHeader("If-None-Match") == stored.ETag {
+		c.Status(http.StatusNotModified)
 		return
 	}
 
-	// Check If-Match header for conditional writes
-	ifMatch := c.GetHeader("If-Match")
-	if ifMatch != "" && ifMatch != generateETag(*existingPlan) {
-		c.JSON(http.StatusPreconditionFailed, gin.H{"error": "Resource has been modified"})
+	// Return the plan with ETag and the schema version it was validated against
+	if stored.SchemaVersion != "" {
+		c.Header("X-Schema-Version", stored.SchemaVersion)
+	}
+	c.Header("ETag", stored.ETag)
+	c.JSON(http.StatusOK, stored.Plan)
+}
+
+// DeletePlan removes a plan by ID
+func (service *PlanService) DeletePlan(c *gin.Context) {
+	if err := service.Delete(RequestContext(c), c.Query("id")); err != nil {
+		problems.AbortWithError(c, err)
 		return
 	}
 
+	c.Status(http.StatusNoContent)
+}
+
+// PatchPlan updates specific fields of a plan
+func (service *PlanService) PatchPlan(c *gin.Context) {
+	planID := c.Query("id")
+
 	// Update plan fields
 	var updatedData models.Plan
 	if err := c.ShouldBindJSON(&updatedData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Invalid data")
 		return
 	}
 
-	service.mergePlan(existingPlan, &updatedData)
-
-	// Save the updated plan
-	if err := service.savePlanToRedis(planID, *existingPlan); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update plan"})
+	_, err := service.Patch(RequestContext(c), planID, updatedData, c.GetHeader("If-Match"), c.GetString(validators.SchemaVersionKey))
+	if err != nil {
+		problems.AbortWithError(c, err)
 		return
 	}
 
@@ -206,58 +470,50 @@ func isPlanCostSharesEmpty(planCostShares models.PlanCostShares) bool {
 		planCostShares.ObjectID == "" && planCostShares.ObjectType == ""
 }
 
-// Save plan to Redis
-func (service *PlanService) savePlanToRedis(planID string, plan models.Plan) error {
+// Helper to describe an audited write on the change feed
+func changeEvent(entry audit.Entry, document []byte) changefeed.Event {
+	return changefeed.Event{
+		Type:      entry.Action,
+		PlanID:    entry.PlanID,
+		Org:       entry.Org,
+		ETag:      entry.ETagAfter,
+		Timestamp: entry.Timestamp,
+		Document:  document,
+		RequestID: entry.RequestID,
+	}
+}
+
+// Save plan to Redis along with the schema version it was validated against,
+// appending the audit entry and change event in the same transaction
+func (service *PlanService) savePlanToRedis(ctx context.Context, planID string, plan models.Plan, schemaVersion string, entry audit.Entry) error {
 	planJSON, err := json.Marshal(plan)
 	if err != nil {
 		return err
 	}
-	return service.redisClient.Set(ctx, "plan:"+planID, planJSON, 0).Err()
+	_, err = service.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, "plan:"+planID, planJSON, 0)
+		pipe.Set(ctx, "plan:"+planID+":schemaVersion", schemaVersion, 0)
+		service.auditLog.Append(ctx, pipe, entry)
+		service.changes.Append(ctx, pipe, changeEvent(entry, planJSON))
+		return nil
+	})
+	return err
 }
 
 // SearchPlans performs a search query in Elasticsearch
 func (service *PlanService) SearchPlans(c *gin.Context) {
 	var req models.SearchRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid search query"})
-		return
-	}
-
-	query := map[string]interface{}{
-		"query": map[string]interface{}{
-			"match": map[string]interface{}{
-				req.Key: req.Value,
-			},
-		},
-	}
-	fmt.Println(query)
-	queryBytes, err := json.Marshal(query)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build query"})
+		problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Invalid search query")
 		return
 	}
 
-	client, err := service.esClient.NewClient()
+	result, err := service.Search(RequestContext(c), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create Elasticsearch client"})
-		return
-	}
-
-	searchReq := esapi.SearchRequest{
-		Index: []string{"plans"},
-		Body:  bytes.NewReader(queryBytes),
-	}
-
-	res, err := searchReq.Do(ctx, client)
-	if err != nil || res.IsError() {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to execute search"})
-		return
-	}
-	defer res.Body.Close()
-
-	var result map[string]interface{}
-	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse search results"})
+		if retryAfter := service.esClient.Breaker.RetryAfter(); retryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		}
+		problems.AbortWithError(c, err)
 		return
 	}
 
@@ -268,36 +524,12 @@ func (service *PlanService) SearchPlans(c *gin.Context) {
 func (service *PlanService) UpdatePlan(c *gin.Context) {
 	var updatedPlan models.Plan
 	if err := c.ShouldBindJSON(&updatedPlan); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
-		return
-	}
-
-	// Check if the plan exists
-	existingPlan, err := service.getPlanFromRedis(updatedPlan.ObjectID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plan"})
-		return
-	}
-	if existingPlan == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Plan not found"})
-		return
-	}
-
-	// Validate request schema
-	if !validatePlanSchema(c) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed"})
-		return
-	}
-
-	// Delete the existing plan
-	if err := service.DeletePlanByID(updatedPlan.ObjectID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete existing plan"})
+		problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Invalid data")
 		return
 	}
 
-	// Create the new plan
-	if err := service.savePlanToRedis(updatedPlan.ObjectID, updatedPlan); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create updated plan"})
+	if _, err := service.Update(RequestContext(c), updatedPlan, c.GetString(validators.SchemaVersionKey)); err != nil {
+		problems.AbortWithError(c, err)
 		return
 	}
 
@@ -306,18 +538,9 @@ func (service *PlanService) UpdatePlan(c *gin.Context) {
 
 // DeletePlanByID deletes a plan by ID without using gin.Context
 func (service *PlanService) DeletePlanByID(planID string) error {
-	err := service.redisClient.Del(ctx, "plan:"+planID).Err()
+	err := service.redisClient.Del(ctx, "plan:"+planID, "plan:"+planID+":schemaVersion").Err()
 	if err != nil {
-		log.Printf("Failed to delete plan %s: %v", planID, err)
+		slog.Error("Failed to delete plan", "planId", planID, "error", err)
 	}
 	return err
 }
-
-// ValidatePlanSchema validates the request schema
-func validatePlanSchema(c *gin.Context) bool {
-	var plan models.Plan
-	if err := c.ShouldBindJSON(&plan); err != nil {
-		return false
-	}
-	return true
-}
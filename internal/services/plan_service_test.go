@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"BigDataForge/internal/models"
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/tenancy"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+const storedPlan = `{
+	"_org": "a.com", "objectId": "p1", "objectType": "plan", "planType": "inNetwork", "creationDate": "12-12-2017",
+	"planCostShares": {"_org": "a.com", "objectId": "c1", "objectType": "membercostshare", "copay": 23, "deductible": 2000},
+	"linkedPlanServices": [{
+		"_org": "a.com", "objectId": "ps1", "objectType": "planservice",
+		"linkedService": {"_org": "a.com", "objectId": "s1", "objectType": "service", "name": "Yearly physical"},
+		"planserviceCostShares": {"_org": "a.com", "objectId": "c2", "objectType": "membercostshare", "copay": 0, "deductible": 10}
+	}]
+}`
+
+// Helper to create a plan service on miniredis holding storedPlan, and the
+// context of an editor of a.com and b.com
+func newTestPlanService(t *testing.T) (*PlanService, context.Context) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+	if err := redisClient.Set(context.Background(), "plan:p1", storedPlan, 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	tenant := &tenancy.Tenant{Subject: "alice", Memberships: map[string]tenancy.Role{"a.com": tenancy.RoleEditor, "b.com": tenancy.RoleEditor}}
+	return NewPlanService(redisClient, nil), tenancy.NewContext(context.Background(), tenant)
+}
+
+func TestPatchChecksRulesOnTheMergedPlan(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		patch       string
+		wantPointer string // of the violation, empty when the patch applies
+	}{
+		{"valid patch", `{"planType": "outOfNetwork"}`, ""},
+		// Each patch is valid on its own
+		{"root moved to another org", `{"_org": "b.com"}`, "/linkedPlanServices/0/_org"},
+		{"objectId of an existing object", `{"linkedPlanServices": [{
+			"_org": "a.com", "objectId": "ps2", "objectType": "planservice",
+			"linkedService": {"_org": "a.com", "objectId": "c1", "objectType": "service", "name": "Well baby"},
+			"planserviceCostShares": {"_org": "a.com", "objectId": "c3", "objectType": "membercostshare"}
+		}]}`, "/planCostShares/objectId"},
+	} {
+		service, ctx := newTestPlanService(t)
+		var patch models.Plan
+		if err := json.Unmarshal([]byte(tc.patch), &patch); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := service.Patch(ctx, "p1", patch, "", "v1")
+		stored, _ := service.getPlanFromRedis(ctx, "p1")
+		if tc.wantPointer == "" {
+			if err != nil || stored.PlanType != "outOfNetwork" {
+				t.Errorf("%s: Patch = %v, stored plan type %q", tc.name, err, stored.PlanType)
+			}
+			continue
+		}
+
+		var problem *problems.Error
+		if !errors.As(err, &problem) || problem.Status != http.StatusUnprocessableEntity || problem.Code != problems.CodeRuleViolation {
+			t.Errorf("%s: Patch = %v, want a business rule violation", tc.name, err)
+			continue
+		}
+		if len(problem.Errors) == 0 || problem.Errors[0].Pointer != tc.wantPointer {
+			t.Errorf("%s: field errors = %+v, want one at %s", tc.name, problem.Errors, tc.wantPointer)
+		}
+		if stored.Org != "a.com" || len(stored.LinkedPlanServices) != 1 || stored.LinkedPlanServices[0].ObjectID != "ps1" {
+			t.Errorf("%s: the rejected patch was stored: %+v", tc.name, stored)
+		}
+	}
+}
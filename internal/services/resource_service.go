@@ -0,0 +1,328 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"BigDataForge/internal/elastic"
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/resources"
+	"BigDataForge/internal/tenancy"
+	"BigDataForge/internal/validators"
+
+	"github.com/elastic/go-elasticsearch/esapi"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// ResourceService serves CRUD operations for one schema-registered resource type.
+type ResourceService struct {
+	redisClient *redis.Client
+	esClient    *elastic.Factory
+	resource    *resources.Resource
+	indexMu     sync.Mutex
+	indexReady  bool // set once the index exists; until then every write tries to create it
+}
+
+func NewResourceService(redisClient *redis.Client, esFactory *elastic.Factory, resource *resources.Resource) *ResourceService {
+	return &ResourceService{
+		redisClient: redisClient,
+		esClient:    esFactory,
+		resource:    resource,
+	}
+}
+
+// Helper to decode the request body keeping numbers intact
+func decodeDocument(c *gin.Context) (map[string]interface{}, error) {
+	var doc map[string]interface{}
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Helper to read the org a document belongs to
+func documentOrg(doc map[string]interface{}) string {
+	org, _ := doc["_org"].(string)
+	return org
+}
+
+// CreateResource stores a new document
+func (service *ResourceService) CreateResource(c *gin.Context) {
+	doc, err := decodeDocument(c)
+	if err != nil {
+		problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Invalid data")
+		return
+	}
+
+	if !authorizeOrg(c, documentOrg(doc), tenancy.RoleEditor, "") {
+		return
+	}
+
+	id := resources.ObjectID(doc)
+	exists, err := service.resource.Exists(c.Request.Context(), service.redisClient, id)
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to check existing data")
+		return
+	}
+	if exists {
+		problems.Abort(c, http.StatusConflict, problems.CodeConflict, fmt.Sprintf("%s already exists", service.resource.Name))
+		return
+	}
+
+	if err := service.resource.Save(c.Request.Context(), service.redisClient, doc); err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, fmt.Sprintf("Failed to store %s", service.resource.Name))
+		return
+	}
+	service.indexResource(c.Request.Context(), doc)
+
+	c.Header("ETag", resources.ETag(doc))
+	c.JSON(http.StatusCreated, gin.H{"message": fmt.Sprintf("%s created", service.resource.Name), "objectId": id})
+}
+
+// GetResource retrieves a document by ID
+func (service *ResourceService) GetResource(c *gin.Context) {
+	id := c.Query("id")
+
+	doc, err := service.resource.Load(c.Request.Context(), service.redisClient, id)
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, fmt.Sprintf("Failed to retrieve %s", service.resource.Name))
+		return
+	}
+	if doc == nil {
+		problems.Abort(c, http.StatusNotFound, problems.CodeNotFound, fmt.Sprintf("%s not found", service.resource.Name))
+		return
+	}
+	if !authorizeOrg(c, documentOrg(doc), tenancy.RoleViewer, fmt.Sprintf("%s not found", service.resource.Name)) {
+		return
+	}
+
+	eTag := resources.ETag(doc)
+	if c.GetHeader("If-None-Match") == eTag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Header("ETag", eTag)
+	c.JSON(http.StatusOK, doc)
+}
+
+// UpdateResource replaces a whole document
+func (service *ResourceService) UpdateResource(c *gin.Context) {
+	doc, err := decodeDocument(c)
+	if err != nil {
+		problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Invalid data")
+		return
+	}
+
+	id := resources.ObjectID(doc)
+	existing, ok := service.loadForWrite(c, id)
+	if !ok || !authorizeOrg(c, documentOrg(doc), tenancy.RoleEditor, "") {
+		return
+	}
+
+	if err := service.resource.Replace(c.Request.Context(), service.redisClient, id, doc); err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, fmt.Sprintf("Failed to update %s", service.resource.Name))
+		return
+	}
+	service.unindexResource(c.Request.Context(), existing)
+	service.indexResource(c.Request.Context(), doc)
+
+	c.Header("ETag", resources.ETag(doc))
+	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("%s updated", service.resource.Name), "objectId": id})
+}
+
+// PatchResource merges a partial document into the stored one
+func (service *ResourceService) PatchResource(c *gin.Context) {
+	id := c.Query("id")
+
+	patch, err := decodeDocument(c)
+	if err != nil {
+		problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Invalid data")
+		return
+	}
+
+	existing, ok := service.loadForWrite(c, id)
+	if !ok {
+		return
+	}
+
+	merged := service.resource.Merge(existing, patch)
+	merged["objectId"] = existing["objectId"]
+	if !authorizeOrg(c, documentOrg(merged), tenancy.RoleEditor, "") {
+		return
+	}
+
+	// The merged document must still satisfy the full schema
+	result, err := service.resource.Schema.Validate(gojsonschema.NewGoLoader(merged))
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to validate patched document")
+		return
+	}
+	if !result.Valid() {
+		problems.AbortWithErrors(c, http.StatusBadRequest, problems.CodeValidationFailed, "Patched document is invalid", validators.SchemaFieldErrors(service.resource.Name, result.Errors()))
+		return
+	}
+
+	if err := service.resource.Replace(c.Request.Context(), service.redisClient, id, merged); err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, fmt.Sprintf("Failed to update %s", service.resource.Name))
+		return
+	}
+	service.unindexResource(c.Request.Context(), existing)
+	service.indexResource(c.Request.Context(), merged)
+
+	c.Header("ETag", resources.ETag(merged))
+	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("%s updated", service.resource.Name), "objectId": id})
+}
+
+// DeleteResource removes a document and all of its objects
+func (service *ResourceService) DeleteResource(c *gin.Context) {
+	id := c.Query("id")
+
+	existing, ok := service.loadForWrite(c, id)
+	if !ok {
+		return
+	}
+
+	if err := service.resource.Delete(c.Request.Context(), service.redisClient, id); err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, fmt.Sprintf("Failed to delete %s", service.resource.Name))
+		return
+	}
+	service.unindexResource(c.Request.Context(), existing)
+
+	c.Status(http.StatusNoContent)
+}
+
+// Helper to load a document and check the caller's role and the If-Match precondition before a write
+func (service *ResourceService) loadForWrite(c *gin.Context, id string) (map[string]interface{}, bool) {
+	existing, err := service.resource.Load(c.Request.Context(), service.redisClient, id)
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, fmt.Sprintf("Failed to retrieve %s", service.resource.Name))
+		return nil, false
+	}
+	if existing == nil {
+		problems.Abort(c, http.StatusNotFound, problems.CodeNotFound, fmt.Sprintf("%s not found", service.resource.Name))
+		return nil, false
+	}
+	if !authorizeOrg(c, documentOrg(existing), tenancy.RoleEditor, fmt.Sprintf("%s not found", service.resource.Name)) {
+		return nil, false
+	}
+
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch != "" && ifMatch != resources.ETag(existing) {
+		problems.Abort(c, http.StatusPreconditionFailed, problems.CodePreconditionFailed, "Resource has been modified")
+		return nil, false
+	}
+	return existing, true
+}
+
+// Index every object of the document as a parent-child tree within the
+// request's context. Redis stays the source of truth, so indexing failures are
+// logged rather than returned.
+func (service *ResourceService) indexResource(ctx context.Context, doc map[string]interface{}) {
+	client, err := service.esClient.Client()
+	if err != nil {
+		return
+	}
+	// The index outlives the request that happens to create it
+	service.prepareIndex(context.WithoutCancel(ctx), client)
+
+	for _, document := range service.resource.Documents(doc) {
+		body, err := json.Marshal(document.Body)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to serialize document", "documentId", document.ID, "error", err)
+			continue
+		}
+		req := esapi.IndexRequest{
+			Index:      service.resource.Index,
+			DocumentID: document.ID,
+			Body:       bytes.NewReader(body),
+			Routing:    document.Routing,
+			Refresh:    "true",
+		}
+		res, err := req.Do(ctx, client)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to index document", "index", service.resource.Index, "documentId", document.ID, "error", err)
+			continue
+		}
+		if res.IsError() {
+			slog.ErrorContext(ctx, "Failed to index document", "index", service.resource.Index, "documentId", document.ID, "response", res.String())
+		}
+		res.Body.Close()
+	}
+}
+
+func (service *ResourceService) unindexResource(ctx context.Context, doc map[string]interface{}) {
+	client, err := service.esClient.Client()
+	if err != nil {
+		return
+	}
+
+	for _, document := range service.resource.Documents(doc) {
+		req := esapi.DeleteRequest{
+			Index:      service.resource.Index,
+			DocumentID: document.ID,
+			Routing:    document.Routing,
+			Refresh:    "true",
+		}
+		res, err := req.Do(ctx, client)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to delete document", "index", service.resource.Index, "documentId", document.ID, "error", err)
+			continue
+		}
+		res.Body.Close()
+	}
+}
+
+// Helper to make sure the index exists before the first document is indexed.
+// A failed attempt is retried on the next write.
+func (service *ResourceService) prepareIndex(ctx context.Context, client *elastic.Client) {
+	service.indexMu.Lock()
+	defer service.indexMu.Unlock()
+	if !service.indexReady {
+		service.indexReady = service.ensureIndex(ctx, client)
+	}
+}
+
+// Create the index with the schema-derived mapping if it does not exist yet.
+// It reports whether the index exists afterwards.
+func (service *ResourceService) ensureIndex(ctx context.Context, client *elastic.Client) bool {
+	mapping, err := json.Marshal(service.resource.Mapping())
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to serialize mapping", "index", service.resource.Index, "error", err)
+		return false
+	}
+
+	req := esapi.IndicesCreateRequest{
+		Index: service.resource.Index,
+		Body:  bytes.NewReader(mapping),
+	}
+	res, err := req.Do(ctx, client)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to create index", "index", service.resource.Index, "error", err)
+		return false
+	}
+	defer res.Body.Close()
+
+	if !res.IsError() {
+		return true
+	}
+	var rejection struct {
+		Error struct {
+			Type string `json:"type"`
+		} `json:"error"`
+	}
+	if json.NewDecoder(res.Body).Decode(&rejection) == nil && rejection.Error.Type == "resource_already_exists_exception" {
+		return true
+	}
+	slog.ErrorContext(ctx, "Failed to create index", "index", service.resource.Index, "status", res.Status())
+	return false
+}
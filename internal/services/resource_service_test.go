@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"BigDataForge/internal/config"
+	"BigDataForge/internal/elastic"
+	"BigDataForge/internal/resources"
+)
+
+func TestPrepareIndexRetriesAfterFailure(t *testing.T) {
+	var mu sync.Mutex
+	var creates int
+	statuses := []int{http.StatusInternalServerError, http.StatusOK}
+	cluster := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodPut || r.URL.Path != "/policys" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(statuses[min(creates, len(statuses)-1)])
+		creates++
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(cluster.Close)
+
+	resource, err := resources.NewRegistry().Register("policy", []byte(`{
+		"type": "object",
+		"properties": {"objectId": {"type": "string"}, "objectType": {"type": "string"}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	factory := elastic.NewFactory(config.Elasticsearch{URLs: []string{cluster.URL}})
+	client, err := factory.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := NewResourceService(nil, factory, resource)
+
+	for i, want := range []struct {
+		creates int
+		ready   bool
+	}{
+		{1, false}, // the cluster fails to create the index
+		{2, true},  // the next write tries again
+		{2, true},  // and later writes no longer do
+	} {
+		service.prepareIndex(context.Background(), client)
+		mu.Lock()
+		got := creates
+		mu.Unlock()
+		if got != want.creates || service.indexReady != want.ready {
+			t.Errorf("write %d: %d create requests, ready %v, want %d, %v", i+1, got, service.indexReady, want.creates, want.ready)
+		}
+	}
+}
+
+func TestEnsureIndexAcceptsExistingIndex(t *testing.T) {
+	cluster := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"type":"resource_already_exists_exception","reason":"index [policys] already exists"},"status":400}`))
+	}))
+	t.Cleanup(cluster.Close)
+
+	resource, err := resources.NewRegistry().Register("policy", []byte(`{
+		"type": "object",
+		"properties": {"objectId": {"type": "string"}, "objectType": {"type": "string"}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	factory := elastic.NewFactory(config.Elasticsearch{URLs: []string{cluster.URL}})
+	client, err := factory.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !NewResourceService(nil, factory, resource).ensureIndex(context.Background(), client) {
+		t.Error("ensureIndex failed on an existing index")
+	}
+}
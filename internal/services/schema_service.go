@@ -0,0 +1,109 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/schemas"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SchemaService exposes the versioned schema registry to administrators.
+type SchemaService struct {
+	registry *schemas.Registry
+}
+
+func NewSchemaService(registry *schemas.Registry) *SchemaService {
+	return &SchemaService{registry: registry}
+}
+
+type activateSchemaRequest struct {
+	Version string `json:"version" binding:"required"`
+}
+
+// ListSchemas lists every schema name with its active version
+func (service *SchemaService) ListSchemas(c *gin.Context) {
+	result := make([]gin.H, 0)
+	for _, name := range service.registry.Names() {
+		active, err := service.registry.ActiveVersion(c.Request.Context(), name)
+		if err != nil {
+			problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to read active schema version")
+			return
+		}
+		result = append(result, gin.H{"name": name, "activeVersion": active})
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ListSchemaVersions lists the versions stored for a schema
+func (service *SchemaService) ListSchemaVersions(c *gin.Context) {
+	versions, err := service.registry.List(c.Request.Context(), c.Param("name"))
+	if errors.Is(err, schemas.ErrUnknownSchema) {
+		problems.Abort(c, http.StatusNotFound, problems.CodeNotFound, "Schema not found")
+		return
+	}
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to list schema versions")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"name": c.Param("name"), "versions": versions})
+}
+
+// GetSchemaVersion returns the schema document of one version
+func (service *SchemaService) GetSchemaVersion(c *gin.Context) {
+	raw, err := service.registry.Raw(c.Request.Context(), c.Param("name"), c.Param("version"))
+	if errors.Is(err, schemas.ErrUnknownSchema) || errors.Is(err, schemas.ErrUnknownVersion) {
+		problems.Abort(c, http.StatusNotFound, problems.CodeNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to read schema")
+		return
+	}
+	c.Data(http.StatusOK, "application/schema+json", raw)
+}
+
+// UploadSchema stores the request body as a new, inactive schema version
+func (service *SchemaService) UploadSchema(c *gin.Context) {
+	raw, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Failed to read request body")
+		return
+	}
+
+	version, err := service.registry.Upload(c.Request.Context(), c.Param("name"), raw)
+	if errors.Is(err, schemas.ErrUnknownSchema) {
+		problems.Abort(c, http.StatusNotFound, problems.CodeNotFound, "Schema not found")
+		return
+	}
+	if err != nil {
+		problems.Abort(c, http.StatusBadRequest, problems.CodeValidationFailed, "Invalid schema: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Schema version uploaded", "name": c.Param("name"), "version": version})
+}
+
+// ActivateSchema switches validation to a stored version
+func (service *SchemaService) ActivateSchema(c *gin.Context) {
+	var req activateSchemaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Invalid data")
+		return
+	}
+
+	err := service.registry.Activate(c.Request.Context(), c.Param("name"), req.Version)
+	if errors.Is(err, schemas.ErrUnknownSchema) || errors.Is(err, schemas.ErrUnknownVersion) {
+		problems.Abort(c, http.StatusNotFound, problems.CodeNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to activate schema version")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Schema version activated", "name": c.Param("name"), "version": req.Version})
+}
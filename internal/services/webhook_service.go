@@ -0,0 +1,171 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"BigDataForge/internal/auth"
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/tenancy"
+	"BigDataForge/internal/webhooks"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookService manages webhook subscriptions. Org admins manage the
+// subscriptions of their orgs.
+type WebhookService struct {
+	store *webhooks.Store
+}
+
+func NewWebhookService(store *webhooks.Store) *WebhookService {
+	return &WebhookService{store: store}
+}
+
+type webhookRequest struct {
+	URL    string   `json:"url" binding:"required"`
+	Events []string `json:"events"`
+	Org    string   `json:"org" binding:"required"`
+	Secret string   `json:"secret"`
+}
+
+// Helper to hide the signing secret of a subscription
+func withoutSecret(sub *webhooks.Subscription) *webhooks.Subscription {
+	public := *sub
+	public.Secret = ""
+	return &public
+}
+
+// Helper to validate the endpoint URL and event types of a subscription request
+func validateWebhookRequest(req *webhookRequest) string {
+	endpoint, err := url.Parse(req.URL)
+	if err != nil || (endpoint.Scheme != "https" && endpoint.Scheme != "http") || endpoint.Host == "" {
+		return "url must be an absolute http or https URL"
+	}
+	if err := webhooks.CheckHost(endpoint.Hostname()); err != nil {
+		return "url must not point to a loopback, private or link-local address"
+	}
+	for _, eventType := range req.Events {
+		known := false
+		for _, candidate := range webhooks.EventTypes {
+			known = known || candidate == eventType
+		}
+		if !known {
+			return fmt.Sprintf("unknown event type %q, expected one of %v", eventType, webhooks.EventTypes)
+		}
+	}
+	return ""
+}
+
+// CreateWebhook subscribes an endpoint to plan events of an org; the secret is only returned here
+func (service *WebhookService) CreateWebhook(c *gin.Context) {
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Invalid data")
+		return
+	}
+	if detail := validateWebhookRequest(&req); detail != "" {
+		problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, detail)
+		return
+	}
+	if !authorizeOrg(c, req.Org, tenancy.RoleAdmin, "") {
+		return
+	}
+
+	sub := &webhooks.Subscription{URL: req.URL, Events: req.Events, Org: req.Org, Secret: req.Secret}
+	if principal, ok := c.MustGet(auth.PrincipalKey).(*auth.Principal); ok {
+		sub.CreatedBy = principal.ID()
+	}
+	if err := service.store.Create(c.Request.Context(), sub); err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to create webhook")
+		return
+	}
+	c.JSON(http.StatusCreated, sub)
+}
+
+// ListWebhooks lists the subscriptions of every org the caller administers
+func (service *WebhookService) ListWebhooks(c *gin.Context) {
+	subs, err := service.store.List(c.Request.Context())
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to list webhooks")
+		return
+	}
+
+	tenant := tenancy.FromContext(c)
+	visible := []*webhooks.Subscription{}
+	for _, sub := range subs {
+		if tenant.Can(sub.Org, tenancy.RoleAdmin) {
+			visible = append(visible, withoutSecret(sub))
+		}
+	}
+	c.JSON(http.StatusOK, visible)
+}
+
+// Helper to load a subscription the caller administers
+func (service *WebhookService) loadWebhook(c *gin.Context) (*webhooks.Subscription, bool) {
+	sub, err := service.store.Get(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, webhooks.ErrUnknownSubscription) {
+		problems.Abort(c, http.StatusNotFound, problems.CodeNotFound, "Webhook not found")
+		return nil, false
+	}
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to retrieve webhook")
+		return nil, false
+	}
+	if !authorizeOrg(c, sub.Org, tenancy.RoleAdmin, "Webhook not found") {
+		return nil, false
+	}
+	return sub, true
+}
+
+// GetWebhook returns one subscription
+func (service *WebhookService) GetWebhook(c *gin.Context) {
+	sub, ok := service.loadWebhook(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, withoutSecret(sub))
+}
+
+// DeleteWebhook removes a subscription
+func (service *WebhookService) DeleteWebhook(c *gin.Context) {
+	sub, ok := service.loadWebhook(c)
+	if !ok {
+		return
+	}
+	if err := service.store.Delete(c.Request.Context(), sub.ID); err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to delete webhook")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// EnableWebhook reactivates a subscription that was disabled after failing deliveries
+func (service *WebhookService) EnableWebhook(c *gin.Context) {
+	sub, ok := service.loadWebhook(c)
+	if !ok {
+		return
+	}
+	if err := service.store.Enable(c.Request.Context(), sub.ID); err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to enable webhook")
+		return
+	}
+	sub.Active, sub.ConsecutiveFailures, sub.DisabledReason = true, 0, ""
+	c.JSON(http.StatusOK, withoutSecret(sub))
+}
+
+// ListWebhookDeliveries returns the latest delivery attempts of a subscription
+func (service *WebhookService) ListWebhookDeliveries(c *gin.Context) {
+	sub, ok := service.loadWebhook(c)
+	if !ok {
+		return
+	}
+	attempts, err := service.store.Deliveries(c.Request.Context(), sub.ID, 100)
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to read delivery log")
+		return
+	}
+	c.JSON(http.StatusOK, attempts)
+}
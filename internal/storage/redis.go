@@ -3,38 +3,42 @@ package storage
 import (
 	"context"
 	"log"
-	"os"
+	"log/slog"
 	"time"
 
+	"BigDataForge/internal/config"
+	"BigDataForge/internal/metrics"
+	"BigDataForge/internal/tracing"
+
 	"github.com/go-redis/redis/v8"
 )
 
 var ctx = context.Background()
 
-func NewRedisClient() *redis.Client {
-	redisAddr := os.Getenv("REDIS_ADDR")
-	if redisAddr == "" {
-		redisAddr = "localhost:6379" // Default address for Redis
-	}
+func NewRedisClient(cfg config.Redis) *redis.Client {
+	return newClient(cfg, &redis.Options{})
+}
 
-	redisPassword := os.Getenv("REDIS_PASSWORD")
-	redisDB := os.Getenv("REDIS_DB")
-	if redisDB == "" {
-		redisDB = "0"
-	}
+// NewChangeFeedClient returns a client for the blocking reads of change feed
+// subscribers. Each subscriber holds a connection while it waits for events,
+// so they get a pool of their own instead of starving the shared client.
+func NewChangeFeedClient(cfg config.Redis) *redis.Client {
+	return newClient(cfg, &redis.Options{PoolSize: cfg.ChangeFeedPoolSize})
+}
 
-	client := redis.NewClient(&redis.Options{
-		Addr:     redisAddr,
-		Password: redisPassword,
-		DB:       0,
-	})
+// Helper to connect a client with the configured server and options
+func newClient(cfg config.Redis, options *redis.Options) *redis.Client {
+	options.Addr, options.Password, options.DB = cfg.Addr, cfg.Password, cfg.DB
+	client := redis.NewClient(options)
+	client.AddHook(metrics.RedisHook{})
+	client.AddHook(tracing.RedisHook{})
 
 	_, err := client.Ping(ctx).Result()
 	if err != nil {
 		log.Fatalf("Failed to connect to Redis: %v", err)
 	}
 
-	log.Println("Connected to Redis at", redisAddr)
+	slog.Info("Connected to Redis", "addr", cfg.Addr, "poolSize", client.Options().PoolSize)
 
 	return client
 }
@@ -42,7 +46,7 @@ func NewRedisClient() *redis.Client {
 func Set(client *redis.Client, key string, value string, expiration time.Duration) error {
 	err := client.Set(ctx, key, value, expiration).Err()
 	if err != nil {
-		log.Printf("Failed to set key: %s in Redis: %v", key, err)
+		slog.Error("Failed to set key in Redis", "key", key, "error", err)
 		return err
 	}
 	return nil
@@ -51,10 +55,10 @@ func Set(client *redis.Client, key string, value string, expiration time.Duratio
 func Get(client *redis.Client, key string) (string, error) {
 	value, err := client.Get(ctx, key).Result()
 	if err == redis.Nil {
-		log.Printf("Key not found: %s", key)
+		slog.Debug("Key not found in Redis", "key", key)
 		return "", nil
 	} else if err != nil {
-		log.Printf("Failed to get key: %s from Redis: %v", key, err)
+		slog.Error("Failed to get key from Redis", "key", key, "error", err)
 		return "", err
 	}
 	return value, nil
@@ -63,7 +67,7 @@ func Get(client *redis.Client, key string) (string, error) {
 func Del(client *redis.Client, key string) error {
 	err := client.Del(ctx, key).Err()
 	if err != nil {
-		log.Printf("Failed to delete key: %s from Redis: %v", key, err)
+		slog.Error("Failed to delete key from Redis", "key", key, "error", err)
 		return err
 	}
 	return nil
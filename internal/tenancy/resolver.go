@@ -0,0 +1,127 @@
+package tenancy
+
+import (
+	"context"
+	"strings"
+
+	"BigDataForge/internal/auth"
+)
+
+// Resolver maps authenticated principals to their org memberships. Memberships
+// come from three places and the highest role per org wins:
+//
+//   - token claims: "orgs" as an object of org -> role, or "org" with "role" or "roles",
+//     only read from tokens of the issuers in ClaimIssuers (TENANCY_CLAIM_ISSUERS)
+//   - memberships granted through the admin API and kept in the Store
+//   - the configured admins (TENANCY_ADMINS), a list of principal IDs or verified emails that are global admins
+//
+// Principals are known by their ID, the subject qualified with the auth method
+// (for example "oidc:1234"), so that a subject chosen by one authenticator
+// never matches the memberships of another.
+//
+// The roles of restricted principals are then capped by their scopes:
+// auth.ScopeRead allows at most viewer, auth.ScopeWrite editor and
+// auth.ScopeAdmin admin. Restricted principals without any of these scopes get
+// no access.
+type Resolver struct {
+	Store        *Store
+	Admins       map[string]bool
+	ClaimIssuers map[string]bool
+}
+
+func NewResolver(store *Store, admins, claimIssuers []string) *Resolver {
+	return &Resolver{Store: store, Admins: toSet(admins), ClaimIssuers: toSet(claimIssuers)}
+}
+
+// Resolve returns the tenant of a principal.
+func (r *Resolver) Resolve(ctx context.Context, principal *auth.Principal) (*Tenant, error) {
+	tenant := newTenant(principal.ID())
+
+	if r.Admins[tenant.Subject] || (principal.Email != "" && emailVerified(principal.Claims) && r.Admins[principal.Email]) {
+		tenant.grant(GlobalOrg, RoleAdmin)
+	}
+
+	if issuer, _ := principal.Claims["iss"].(string); issuer != "" && r.ClaimIssuers[issuer] {
+		membershipsFromClaims(tenant, principal.Claims)
+	}
+
+	stored, err := r.Store.Memberships(ctx, tenant.Subject)
+	if err != nil {
+		return nil, err
+	}
+	for org, role := range stored {
+		tenant.grant(org, role)
+	}
+
+	if principal.Restricted {
+		tenant.limit(scopeRole(principal))
+	}
+	return tenant, nil
+}
+
+// Helper to build a set of the non-empty values of a list
+func toSet(values []string) map[string]bool {
+	set := map[string]bool{}
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			set[value] = true
+		}
+	}
+	return set
+}
+
+// Helper to check that a token vouches for its email, as a boolean or a
+// "true" string depending on the issuer
+func emailVerified(claims map[string]interface{}) bool {
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		return verified
+	case string:
+		return verified == "true"
+	}
+	return false
+}
+
+// Helper to find the highest role the scopes of a principal allow
+func scopeRole(principal *auth.Principal) Role {
+	switch {
+	case principal.HasScope(auth.ScopeAdmin):
+		return RoleAdmin
+	case principal.HasScope(auth.ScopeWrite):
+		return RoleEditor
+	case principal.HasScope(auth.ScopeRead):
+		return RoleViewer
+	}
+	return ""
+}
+
+// Helper to read memberships carried in token claims
+func membershipsFromClaims(tenant *Tenant, claims map[string]interface{}) {
+	if orgs, ok := claims["orgs"].(map[string]interface{}); ok {
+		for org, name := range orgs {
+			if role, ok := name.(string); ok {
+				tenant.grant(org, Role(role))
+			}
+		}
+	}
+
+	org, _ := claims["org"].(string)
+	if org == "" {
+		return
+	}
+	if role, ok := claims["role"].(string); ok {
+		tenant.grant(org, Role(role))
+	}
+	switch roles := claims["roles"].(type) {
+	case string:
+		for _, role := range strings.Fields(roles) {
+			tenant.grant(org, Role(role))
+		}
+	case []interface{}:
+		for _, role := range roles {
+			if name, ok := role.(string); ok {
+				tenant.grant(org, Role(name))
+			}
+		}
+	}
+}
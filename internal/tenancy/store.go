@@ -0,0 +1,55 @@
+package tenancy
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Memberships granted through the admin API live in Redis:
+//
+//	tenancy:<subject>  hash of org -> role
+
+var ErrUnknownMembership = errors.New("unknown membership")
+
+// Store keeps org memberships of principals in Redis.
+type Store struct {
+	redisClient *redis.Client
+}
+
+func NewStore(redisClient *redis.Client) *Store {
+	return &Store{redisClient: redisClient}
+}
+
+// Memberships returns the stored memberships of a subject.
+func (s *Store) Memberships(ctx context.Context, subject string) (map[string]Role, error) {
+	fields, err := s.redisClient.HGetAll(ctx, "tenancy:"+subject).Result()
+	if err != nil {
+		return nil, err
+	}
+	memberships := make(map[string]Role, len(fields))
+	for org, name := range fields {
+		if role, ok := ParseRole(name); ok {
+			memberships[org] = role
+		}
+	}
+	return memberships, nil
+}
+
+// Grant sets the role of a subject in an org.
+func (s *Store) Grant(ctx context.Context, subject, org string, role Role) error {
+	return s.redisClient.HSet(ctx, "tenancy:"+subject, org, string(role)).Err()
+}
+
+// Revoke removes a subject's membership in an org.
+func (s *Store) Revoke(ctx context.Context, subject, org string) error {
+	deleted, err := s.redisClient.HDel(ctx, "tenancy:"+subject, org).Result()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrUnknownMembership
+	}
+	return nil
+}
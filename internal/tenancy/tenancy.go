@@ -0,0 +1,125 @@
+package tenancy
+
+import (
+	"context"
+)
+
+// Role is the access level a principal holds in an org.
+type Role string
+
+const (
+	RoleViewer Role = "viewer" // read and search
+	RoleEditor Role = "editor" // viewer plus create, update and delete
+	RoleAdmin  Role = "admin"  // editor plus administration
+)
+
+// GlobalOrg is the membership org that applies to every org.
+const GlobalOrg = "*"
+
+// TenantKey stores the resolved tenant in the gin context.
+const TenantKey = "tenant"
+
+var roleRank = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+// ParseRole validates a role name.
+func ParseRole(name string) (Role, bool) {
+	role := Role(name)
+	_, ok := roleRank[role]
+	return role, ok
+}
+
+// Includes reports whether the role grants at least the required role.
+func (r Role) Includes(required Role) bool {
+	return roleRank[required] > 0 && roleRank[r] >= roleRank[required]
+}
+
+// Tenant is an authenticated principal together with its org memberships.
+type Tenant struct {
+	Subject     string          `json:"subject"`
+	Memberships map[string]Role `json:"memberships"`
+}
+
+func newTenant(subject string) *Tenant {
+	return &Tenant{Subject: subject, Memberships: map[string]Role{}}
+}
+
+// grant records a membership, keeping the higher role when the org is already present.
+func (t *Tenant) grant(org string, role Role) {
+	if org == "" || roleRank[role] == 0 {
+		return
+	}
+	if !t.Memberships[org].Includes(role) {
+		t.Memberships[org] = role
+	}
+}
+
+// limit lowers every membership above role to role. An empty role removes
+// every membership.
+func (t *Tenant) limit(role Role) {
+	for org, granted := range t.Memberships {
+		switch {
+		case roleRank[role] == 0:
+			delete(t.Memberships, org)
+		case granted.Includes(role):
+			t.Memberships[org] = role
+		}
+	}
+}
+
+// RoleIn returns the effective role in an org, taking global memberships into account.
+func (t *Tenant) RoleIn(org string) Role {
+	if t == nil {
+		return ""
+	}
+	role, global := t.Memberships[org], t.Memberships[GlobalOrg]
+	if roleRank[global] > roleRank[role] {
+		return global
+	}
+	return role
+}
+
+// Can reports whether the tenant holds at least the required role in an org.
+func (t *Tenant) Can(org string, required Role) bool {
+	return t.RoleIn(org).Includes(required)
+}
+
+// Orgs lists the orgs in which the tenant holds at least the required role.
+// all is true when a global membership grants access to every org.
+func (t *Tenant) Orgs(required Role) (orgs []string, all bool) {
+	if t == nil {
+		return nil, false
+	}
+	if t.Memberships[GlobalOrg].Includes(required) {
+		return nil, true
+	}
+	orgs = []string{}
+	for org, role := range t.Memberships {
+		if org != GlobalOrg && role.Includes(required) {
+			orgs = append(orgs, org)
+		}
+	}
+	return orgs, false
+}
+
+type tenantContextKey struct{}
+
+// NewContext returns a copy of ctx carrying the tenant.
+func NewContext(ctx context.Context, tenant *Tenant) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// FromContext returns the tenant resolved for the request, or nil. ctx may
+// also be a gin context holding the tenant under TenantKey.
+func FromContext(ctx context.Context) *Tenant {
+	if tenant, ok := ctx.Value(tenantContextKey{}).(*Tenant); ok {
+		return tenant
+	}
+	if tenant, ok := ctx.Value(TenantKey).(*Tenant); ok {
+		return tenant
+	}
+	return nil
+}
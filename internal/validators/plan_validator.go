@@ -2,49 +2,100 @@ package validators
 
 import (
 	"bytes"
-	"io/ioutil"
+	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 
+	"BigDataForge/internal/models"
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/schemas"
+
 	"github.com/gin-gonic/gin"
 	"github.com/xeipuuv/gojsonschema"
 )
 
-const createSchemaPath = "./internal/schemas/plan_schema.json"
-const patchSchemaPath = "./internal/schemas/patch_plan_schema.json"
+// SchemaVersionKey is the context key holding the schema version a request
+// body was validated against, formatted as name@version.
+const SchemaVersionKey = "schemaVersion"
 
-func ValidatePlanSchema(c *gin.Context) bool {
+func ValidatePlanSchema(c *gin.Context, registry *schemas.Registry) bool {
 
-	schemaPath := createSchemaPath
+	schemaName := schemas.PlanSchema
 	if c.Request.Method == http.MethodPatch {
-		schemaPath = patchSchemaPath
+		schemaName = schemas.PatchPlanSchema
 	}
 
-	schemaLoader := gojsonschema.NewReferenceLoader("file://" + schemaPath)
-
-	body, err := ioutil.ReadAll(c.Request.Body)
-	// fmt.Printf("Here is the payload Data : %v", string(body))
+	body, err := io.ReadAll(c.Request.Body)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read request body"})
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to read request body")
 		return false
 	}
 
-	documentLoader := gojsonschema.NewStringLoader(string(body))
-	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
+	schemaVersion, err := ValidatePlanDocument(c.Request.Context(), registry, schemaName, body)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Schema validation failed", "details": err.Error()})
+		problems.AbortWithError(c, err)
 		return false
 	}
 
+	c.Set(SchemaVersionKey, schemaVersion)
+	c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+
+	return true
+}
+
+// ValidatePlanDocument validates a plan body against the active version of a
+// plan schema and returns that version, formatted as name@version.
+func ValidatePlanDocument(ctx context.Context, registry *schemas.Registry, schemaName string, body []byte) (string, error) {
+	schema, version, err := registry.Active(ctx, schemaName)
+	if err != nil {
+		return "", problems.NewError(http.StatusInternalServerError, problems.CodeInternal, "Failed to load schema")
+	}
+
+	documentLoader := gojsonschema.NewBytesLoader(body)
+	result, err := schema.Validate(documentLoader)
+	if err != nil {
+		return "", problems.NewError(http.StatusBadRequest, problems.CodeInvalidRequest, "Request body is not valid JSON")
+	}
+
 	if !result.Valid() {
-		var validationErrors []string
-		for _, desc := range result.Errors() {
-			validationErrors = append(validationErrors, desc.String())
+		return "", &problems.Error{
+			Status: http.StatusBadRequest,
+			Code:   problems.CodeValidationFailed,
+			Detail: "Invalid data",
+			Errors: SchemaFieldErrors(schemaName, result.Errors()),
 		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data", "details": validationErrors})
-		return false
 	}
 
-	c.Request.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+	return schemaName + "@" + version, nil
+}
 
-	return true
+// PlanValidator applies schema and business rule validation to plan documents
+// received outside of the REST handlers, such as gRPC and GraphQL arguments.
+type PlanValidator struct {
+	Schemas *schemas.Registry
+	Rules   *RuleEngine
+}
+
+func NewPlanValidator(registry *schemas.Registry) *PlanValidator {
+	return &PlanValidator{Schemas: registry, Rules: NewRuleEngine(DefaultPlanRules()...)}
+}
+
+// Validate checks a plan document against the active version of a plan schema
+// and the business rules, then decodes it. It returns the plan and the schema
+// version it was validated against.
+func (v *PlanValidator) Validate(ctx context.Context, schemaName string, body []byte) (models.Plan, string, error) {
+	schemaVersion, err := ValidatePlanDocument(ctx, v.Schemas, schemaName, body)
+	if err != nil {
+		return models.Plan{}, "", err
+	}
+	if err := v.Rules.ValidateDocument(body); err != nil {
+		return models.Plan{}, "", err
+	}
+
+	var plan models.Plan
+	if err := json.Unmarshal(body, &plan); err != nil {
+		return models.Plan{}, "", problems.NewError(http.StatusBadRequest, problems.CodeInvalidRequest, "Invalid data")
+	}
+	return plan, schemaVersion, nil
 }
@@ -0,0 +1,43 @@
+package validators
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/resources"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// ValidateResourceSchema validates the request body against the resource's
+// compiled schema, using the patch variant for PATCH requests.
+func ValidateResourceSchema(c *gin.Context, resource *resources.Resource) bool {
+	schema, schemaName := resource.Schema, resource.Name
+	if c.Request.Method == http.MethodPatch {
+		schema, schemaName = resource.PatchSchema, resource.Name+"-patch"
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to read request body")
+		return false
+	}
+
+	result, err := schema.Validate(gojsonschema.NewBytesLoader(body))
+	if err != nil {
+		problems.Abort(c, http.StatusBadRequest, problems.CodeInvalidRequest, "Request body is not valid JSON")
+		return false
+	}
+
+	if !result.Valid() {
+		problems.AbortWithErrors(c, http.StatusBadRequest, problems.CodeValidationFailed, "Invalid data", SchemaFieldErrors(schemaName, result.Errors()))
+		return false
+	}
+
+	c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+
+	return true
+}
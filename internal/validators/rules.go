@@ -0,0 +1,256 @@
+package validators
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"BigDataForge/internal/problems"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Violation is a business rule failure located by a JSON Pointer into the document.
+type Violation struct {
+	Pointer string `json:"pointer"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Rule checks one business constraint on a decoded plan document. Rules only
+// look at the fields present, so they apply to partial (PATCH) bodies as well.
+type Rule interface {
+	Name() string
+	Check(doc map[string]interface{}) []Violation
+}
+
+// RuleFunc adapts a function to the Rule interface.
+type RuleFunc struct {
+	RuleName string
+	Func     func(doc map[string]interface{}) []Violation
+}
+
+func (r RuleFunc) Name() string { return r.RuleName }
+
+func (r RuleFunc) Check(doc map[string]interface{}) []Violation { return r.Func(doc) }
+
+// RuleEngine runs every registered rule and collects all violations.
+type RuleEngine struct {
+	rules []Rule
+}
+
+func NewRuleEngine(rules ...Rule) *RuleEngine {
+	return &RuleEngine{rules: rules}
+}
+
+// Register adds a rule to the engine.
+func (e *RuleEngine) Register(rule Rule) {
+	e.rules = append(e.rules, rule)
+}
+
+// Validate returns the violations of all rules, ordered by pointer.
+func (e *RuleEngine) Validate(doc map[string]interface{}) []Violation {
+	var violations []Violation
+	for _, rule := range e.rules {
+		violations = append(violations, rule.Check(doc)...)
+	}
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Pointer < violations[j].Pointer })
+	return violations
+}
+
+// ValidatePlanRules runs the rule engine on the request body. It is meant to
+// be called after schema validation, so the body is known to be a JSON object.
+func ValidatePlanRules(c *gin.Context, engine *RuleEngine) bool {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		problems.Abort(c, http.StatusInternalServerError, problems.CodeInternal, "Failed to read request body")
+		return false
+	}
+	c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+
+	if err := engine.ValidateDocument(body); err != nil {
+		problems.AbortWithError(c, err)
+		return false
+	}
+	return true
+}
+
+// ValidateDocument decodes a plan body and runs every rule on it. Violations
+// are returned as a business rule violation problem.
+func (e *RuleEngine) ValidateDocument(body []byte) error {
+	var doc map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return problems.NewError(http.StatusBadRequest, problems.CodeInvalidRequest, "Invalid data")
+	}
+
+	violations := e.Validate(doc)
+	if len(violations) == 0 {
+		return nil
+	}
+	fieldErrors := make([]problems.FieldError, 0, len(violations))
+	for _, violation := range violations {
+		fieldErrors = append(fieldErrors, problems.FieldError{
+			Pointer: violation.Pointer,
+			Keyword: violation.Rule,
+			Message: violation.Message,
+		})
+	}
+	return &problems.Error{
+		Status: http.StatusUnprocessableEntity,
+		Code:   problems.CodeRuleViolation,
+		Detail: "Business rule violation",
+		Errors: fieldErrors,
+	}
+}
+
+// DefaultPlanRules are the rules applied to every plan write.
+func DefaultPlanRules() []Rule {
+	return []Rule{
+		RuleFunc{RuleName: "uniqueObjectId", Func: checkUniqueObjectIDs},
+		RuleFunc{RuleName: "orgConsistency", Func: checkOrgConsistency},
+		RuleFunc{RuleName: "objectTypePosition", Func: checkObjectTypes},
+		RuleFunc{RuleName: "nonNegativeCostShares", Func: checkCostShares},
+		RuleFunc{RuleName: "creationDateFormat", Func: checkCreationDate},
+	}
+}
+
+// Expected objectType for each position of the plan tree, keyed by the
+// pointer with array indexes replaced by "*".
+var planObjectTypes = map[string]string{
+	"":                                    "plan",
+	"/planCostShares":                     "membercostshare",
+	"/linkedPlanServices/*":               "planservice",
+	"/linkedPlanServices/*/linkedService": "service",
+	"/linkedPlanServices/*/planserviceCostShares": "membercostshare",
+}
+
+// creationDate is indexed with the Elasticsearch format MM-dd-yyyy
+const creationDateLayout = "01-02-2006"
+
+func checkUniqueObjectIDs(doc map[string]interface{}) []Violation {
+	var violations []Violation
+	seen := make(map[string]string)
+	walkObjects(doc, "", "", func(obj map[string]interface{}, pointer, _ string) {
+		id, ok := obj["objectId"].(string)
+		if !ok {
+			return
+		}
+		if first, dup := seen[id]; dup {
+			violations = append(violations, Violation{
+				Pointer: pointer + "/objectId",
+				Rule:    "uniqueObjectId",
+				Message: fmt.Sprintf("objectId %q is already used at %s", id, first),
+			})
+			return
+		}
+		seen[id] = pointer + "/objectId"
+	})
+	return violations
+}
+
+func checkOrgConsistency(doc map[string]interface{}) []Violation {
+	org, ok := doc["_org"].(string)
+	if !ok {
+		return nil
+	}
+
+	var violations []Violation
+	walkObjects(doc, "", "", func(obj map[string]interface{}, pointer, _ string) {
+		if pointer == "" {
+			return
+		}
+		if childOrg, ok := obj["_org"].(string); ok && childOrg != org {
+			violations = append(violations, Violation{
+				Pointer: pointer + "/_org",
+				Rule:    "orgConsistency",
+				Message: fmt.Sprintf("_org %q does not match the plan's _org %q", childOrg, org),
+			})
+		}
+	})
+	return violations
+}
+
+func checkObjectTypes(doc map[string]interface{}) []Violation {
+	var violations []Violation
+	walkObjects(doc, "", "", func(obj map[string]interface{}, pointer, pattern string) {
+		expected, known := planObjectTypes[pattern]
+		objectType, present := obj["objectType"].(string)
+		if !known || !present || objectType == expected {
+			return
+		}
+		violations = append(violations, Violation{
+			Pointer: pointer + "/objectType",
+			Rule:    "objectTypePosition",
+			Message: fmt.Sprintf("objectType must be %q at this position, got %q", expected, objectType),
+		})
+	})
+	return violations
+}
+
+func checkCostShares(doc map[string]interface{}) []Violation {
+	var violations []Violation
+	walkObjects(doc, "", "", func(obj map[string]interface{}, pointer, _ string) {
+		for _, field := range []string{"copay", "deductible"} {
+			value, ok := obj[field].(json.Number)
+			if !ok {
+				continue
+			}
+			if n, err := value.Float64(); err == nil && n < 0 {
+				violations = append(violations, Violation{
+					Pointer: pointer + "/" + field,
+					Rule:    "nonNegativeCostShares",
+					Message: fmt.Sprintf("%s must not be negative", field),
+				})
+			}
+		}
+	})
+	return violations
+}
+
+func checkCreationDate(doc map[string]interface{}) []Violation {
+	value, ok := doc["creationDate"].(string)
+	if !ok {
+		return nil
+	}
+	if _, err := time.Parse(creationDateLayout, value); err != nil {
+		return []Violation{{
+			Pointer: "/creationDate",
+			Rule:    "creationDateFormat",
+			Message: fmt.Sprintf("creationDate %q must be a valid MM-dd-yyyy date", value),
+		}}
+	}
+	return nil
+}
+
+// walkObjects visits every JSON object in the document with its JSON Pointer
+// and a pattern of that pointer where array indexes are replaced by "*".
+func walkObjects(value interface{}, pointer, pattern string, visit func(obj map[string]interface{}, pointer, pattern string)) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		visit(v, pointer, pattern)
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			token := escapePointerToken(key)
+			walkObjects(v[key], pointer+"/"+token, pattern+"/"+token, visit)
+		}
+	case []interface{}:
+		for i, item := range v {
+			walkObjects(item, fmt.Sprintf("%s/%d", pointer, i), pattern+"/*", visit)
+		}
+	}
+}
+
+func escapePointerToken(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
@@ -0,0 +1,160 @@
+package validators
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"BigDataForge/internal/problems"
+)
+
+const validPlan = `{
+	"_org": "example.com",
+	"objectId": "p1",
+	"objectType": "plan",
+	"planType": "inNetwork",
+	"creationDate": "12-12-2017",
+	"planCostShares": {"_org": "example.com", "objectId": "c1", "objectType": "membercostshare", "copay": 23, "deductible": 2000},
+	"linkedPlanServices": [
+		{
+			"_org": "example.com", "objectId": "ps1", "objectType": "planservice",
+			"linkedService": {"_org": "example.com", "objectId": "s1", "objectType": "service", "name": "Yearly physical"},
+			"planserviceCostShares": {"_org": "example.com", "objectId": "c2", "objectType": "membercostshare", "copay": 0, "deductible": 10}
+		},
+		{
+			"_org": "example.com", "objectId": "ps2", "objectType": "planservice",
+			"linkedService": {"_org": "example.com", "objectId": "s2", "objectType": "service", "name": "Well baby"},
+			"planserviceCostShares": {"_org": "example.com", "objectId": "c3", "objectType": "membercostshare", "copay": 175, "deductible": 10}
+		}
+	]
+}`
+
+// Helper to run the default rules on a plan with replacements applied to its JSON
+func planViolations(t *testing.T, replacements ...string) []Violation {
+	t.Helper()
+	plan := strings.NewReplacer(replacements...).Replace(validPlan)
+	var doc map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(plan))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+	return NewRuleEngine(DefaultPlanRules()...).Validate(doc)
+}
+
+func TestDefaultPlanRules(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		replacements []string
+		want         []Violation
+	}{
+		{"valid plan", nil, nil},
+		{
+			"duplicate objectId",
+			[]string{`"objectId": "s2"`, `"objectId": "c1"`},
+			[]Violation{{Pointer: "/planCostShares/objectId", Rule: "uniqueObjectId", Message: `objectId "c1" is already used at /linkedPlanServices/1/linkedService/objectId`}},
+		},
+		{
+			"child in another org",
+			[]string{`"_org": "example.com", "objectId": "s2"`, `"_org": "other.com", "objectId": "s2"`},
+			[]Violation{{Pointer: "/linkedPlanServices/1/linkedService/_org", Rule: "orgConsistency", Message: `_org "other.com" does not match the plan's _org "example.com"`}},
+		},
+		{
+			"objectType at the wrong position",
+			[]string{`"objectId": "s1", "objectType": "service"`, `"objectId": "s1", "objectType": "planservice"`},
+			[]Violation{{Pointer: "/linkedPlanServices/0/linkedService/objectType", Rule: "objectTypePosition", Message: `objectType must be "service" at this position, got "planservice"`}},
+		},
+		{
+			"root objectType",
+			[]string{`"objectType": "plan"`, `"objectType": "service"`},
+			[]Violation{{Pointer: "/objectType", Rule: "objectTypePosition", Message: `objectType must be "plan" at this position, got "service"`}},
+		},
+		{
+			"negative cost shares",
+			[]string{`"copay": 23`, `"copay": -1`, `"deductible": 2000`, `"deductible": -0.5`},
+			[]Violation{
+				{Pointer: "/planCostShares/copay", Rule: "nonNegativeCostShares", Message: "copay must not be negative"},
+				{Pointer: "/planCostShares/deductible", Rule: "nonNegativeCostShares", Message: "deductible must not be negative"},
+			},
+		},
+		{
+			"invalid creation date",
+			[]string{`"12-12-2017"`, `"2017-12-12"`},
+			[]Violation{{Pointer: "/creationDate", Rule: "creationDateFormat", Message: `creationDate "2017-12-12" must be a valid MM-dd-yyyy date`}},
+		},
+		{
+			"violations of several rules are ordered by pointer",
+			[]string{`"12-12-2017"`, `"13-01-2017"`, `"copay": 175`, `"copay": -175`},
+			[]Violation{
+				{Pointer: "/creationDate", Rule: "creationDateFormat", Message: `creationDate "13-01-2017" must be a valid MM-dd-yyyy date`},
+				{Pointer: "/linkedPlanServices/1/planserviceCostShares/copay", Rule: "nonNegativeCostShares", Message: "copay must not be negative"},
+			},
+		},
+	} {
+		if got := planViolations(t, tc.replacements...); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: violations = %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRulesApplyToPartialDocuments(t *testing.T) {
+	engine := NewRuleEngine(DefaultPlanRules()...)
+	for _, tc := range []struct {
+		patch string
+		want  int
+	}{
+		{`{"planType": "outOfNetwork"}`, 0},
+		// Without a root _org there is nothing to compare the children with
+		{`{"planCostShares": {"_org": "other.com", "objectId": "c1", "objectType": "membercostshare"}}`, 0},
+		{`{"planCostShares": {"objectId": "c1", "objectType": "service"}}`, 1},
+		{`{"linkedPlanServices": [{"objectId": "x"}, {"objectId": "x"}]}`, 1},
+	} {
+		var doc map[string]interface{}
+		if err := json.Unmarshal([]byte(tc.patch), &doc); err != nil {
+			t.Fatal(err)
+		}
+		if got := engine.Validate(doc); len(got) != tc.want {
+			t.Errorf("Validate(%s) = %+v, want %d violations", tc.patch, got, tc.want)
+		}
+	}
+}
+
+func TestRuleEngineRegister(t *testing.T) {
+	engine := NewRuleEngine()
+	engine.Register(RuleFunc{RuleName: "noDental", Func: func(doc map[string]interface{}) []Violation {
+		if doc["planType"] == "dental" {
+			return []Violation{{Pointer: "/planType", Rule: "noDental", Message: "dental plans are not offered"}}
+		}
+		return nil
+	}})
+
+	if got := engine.Validate(map[string]interface{}{"planType": "dental"}); len(got) != 1 || got[0].Rule != "noDental" {
+		t.Errorf("violations = %+v, want the registered rule's", got)
+	}
+}
+
+func TestValidateDocument(t *testing.T) {
+	engine := NewRuleEngine(DefaultPlanRules()...)
+
+	if err := engine.ValidateDocument([]byte(validPlan)); err != nil {
+		t.Errorf("valid plan: %v", err)
+	}
+
+	var problem *problems.Error
+	err := engine.ValidateDocument([]byte(strings.Replace(validPlan, `"copay": 23`, `"copay": -23`, 1)))
+	if !errors.As(err, &problem) || problem.Status != http.StatusUnprocessableEntity || problem.Code != problems.CodeRuleViolation {
+		t.Fatalf("error = %v, want a business rule violation", err)
+	}
+	want := []problems.FieldError{{Pointer: "/planCostShares/copay", Keyword: "nonNegativeCostShares", Message: "copay must not be negative"}}
+	if !reflect.DeepEqual(problem.Errors, want) {
+		t.Errorf("field errors = %+v, want %+v", problem.Errors, want)
+	}
+
+	err = engine.ValidateDocument([]byte(`[1, 2]`))
+	if !errors.As(err, &problem) || problem.Status != http.StatusBadRequest {
+		t.Errorf("error = %v, want an invalid request", err)
+	}
+}
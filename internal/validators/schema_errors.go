@@ -0,0 +1,83 @@
+package validators
+
+import (
+	"strings"
+
+	"BigDataForge/internal/metrics"
+	"BigDataForge/internal/problems"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// gojsonschema error types that differ from the JSON Schema keyword they report on
+var schemaKeywords = map[string]string{
+	"invalid_type":                    "type",
+	"number_any_of":                   "anyOf",
+	"number_one_of":                   "oneOf",
+	"number_all_of":                   "allOf",
+	"number_not":                      "not",
+	"array_no_additional_items":       "additionalItems",
+	"array_min_items":                 "minItems",
+	"array_max_items":                 "maxItems",
+	"unique":                          "uniqueItems",
+	"contains":                        "contains",
+	"array_min_properties":            "minProperties",
+	"array_max_properties":            "maxProperties",
+	"additional_property_not_allowed": "additionalProperties",
+	"invalid_property_pattern":        "patternProperties",
+	"invalid_property_name":           "propertyNames",
+	"string_gte":                      "minLength",
+	"string_lte":                      "maxLength",
+	"pattern":                         "pattern",
+	"multiple_of":                     "multipleOf",
+	"number_gte":                      "minimum",
+	"number_gt":                       "exclusiveMinimum",
+	"number_lte":                      "maximum",
+	"number_lt":                       "exclusiveMaximum",
+	"condition_then":                  "then",
+	"condition_else":                  "else",
+	"missing_dependency":              "dependencies",
+}
+
+// SchemaFieldErrors converts gojsonschema results into field errors located by
+// JSON Pointer. Missing required properties point at the property itself.
+// Each error is counted against the schema and keyword it failed.
+func SchemaFieldErrors(schemaName string, results []gojsonschema.ResultError) []problems.FieldError {
+	fieldErrors := make([]problems.FieldError, 0, len(results))
+	for _, result := range results {
+		pointer := fieldPointer(result.Field())
+		if result.Type() == "required" {
+			if property, ok := result.Details()["property"].(string); ok {
+				pointer += "/" + escapePointerToken(property)
+			}
+		}
+
+		keyword, ok := schemaKeywords[result.Type()]
+		if !ok {
+			keyword = result.Type()
+		}
+		metrics.ValidationFailures.WithLabelValues(schemaName, keyword).Inc()
+
+		fieldErrors = append(fieldErrors, problems.FieldError{
+			Pointer: pointer,
+			Keyword: keyword,
+			Message: result.Description(),
+		})
+	}
+	return fieldErrors
+}
+
+// gojsonschema reports fields as dotted paths such as
+// "linkedPlanServices.0.objectId", with "(root)" for the document itself.
+func fieldPointer(field string) string {
+	if field == "" || field == gojsonschema.STRING_CONTEXT_ROOT {
+		return ""
+	}
+	field = strings.TrimPrefix(field, gojsonschema.STRING_CONTEXT_ROOT+".")
+
+	tokens := strings.Split(field, ".")
+	for i, token := range tokens {
+		tokens[i] = escapePointerToken(token)
+	}
+	return "/" + strings.Join(tokens, "/")
+}
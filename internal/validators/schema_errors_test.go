@@ -0,0 +1,141 @@
+package validators
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"BigDataForge/internal/problems"
+	"BigDataForge/internal/schemas"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestSchemaFieldErrors(t *testing.T) {
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(`{
+		"type": "object",
+		"required": ["name", "a/b"],
+		"properties": {
+			"name": {"type": "string"},
+			"a/b": {"type": "integer"},
+			"c~d": {"type": "string", "maxLength": 2},
+			"grid": {"type": "array", "items": {"type": "array", "items": {"type": "number", "minimum": 0}}},
+			"services": {"type": "array", "items": {
+				"type": "object",
+				"required": ["objectId", "m~n/o"],
+				"properties": {"objectId": {"type": "string"}, "m~n/o": {"type": "string"}},
+				"additionalProperties": false
+			}}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := schema.Validate(gojsonschema.NewStringLoader(`{
+		"c~d": "long",
+		"grid": [[1, 2], [3, -4]],
+		"services": [
+			{"objectId": "s1", "m~n/o": "x"},
+			{"m~n/o": 1, "extra": true}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]string)
+	for _, fieldError := range SchemaFieldErrors("test", result.Errors()) {
+		got[fieldError.Pointer+" "+fieldError.Keyword] = fieldError.Message
+	}
+	for _, want := range []string{
+		"/name required",                // missing properties point at the property
+		"/a~1b required",                // "/" is escaped as ~1
+		"/c~0d maxLength",               // "~" is escaped as ~0
+		"/grid/1/1 minimum",             // array indexes at every depth
+		"/services/1/objectId required", // inside array items
+		"/services/1/m~0n~1o type",      // both escapes in one token
+		"/services/1 additionalProperties",
+	} {
+		if _, ok := got[want]; !ok {
+			t.Errorf("no field error %q in %v", want, got)
+		}
+	}
+	if len(got) != 7 {
+		t.Errorf("got %d field errors, want 7: %v", len(got), got)
+	}
+}
+
+func TestFieldPointer(t *testing.T) {
+	for _, tc := range []struct {
+		field string
+		want  string
+	}{
+		{"", ""},
+		{"(root)", ""},
+		{"(root).objectId", "/objectId"},
+		{"linkedPlanServices.0.linkedService.objectId", "/linkedPlanServices/0/linkedService/objectId"},
+		{"matrix.2.10", "/matrix/2/10"},
+		{"a/b.c~d", "/a~1b/c~0d"},
+		{"~1", "/~01"},
+	} {
+		if got := fieldPointer(tc.field); got != tc.want {
+			t.Errorf("fieldPointer(%q) = %q, want %q", tc.field, got, tc.want)
+		}
+	}
+}
+
+func TestValidatePlanSchemaWritesProblem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+	registry, err := schemas.NewRegistry(redisClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := registry.Active(context.Background(), schemas.PlanSchema); err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	router.POST("/api/v1/plans", func(c *gin.Context) {
+		if ValidatePlanSchema(c, registry) {
+			c.Status(http.StatusCreated)
+		}
+	})
+	body := strings.Replace(validPlan, `"objectId": "s2", `, "", 1)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/plans", strings.NewReader(body)))
+
+	if recorder.Code != http.StatusBadRequest || recorder.Header().Get("Content-Type") != problems.ContentType {
+		t.Fatalf("response = %d %s, want 400 %s", recorder.Code, recorder.Header().Get("Content-Type"), problems.ContentType)
+	}
+	var problem problems.Problem
+	if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	want := problems.Problem{
+		Type:     problems.TypeURI(problems.CodeValidationFailed),
+		Title:    "Bad Request",
+		Status:   http.StatusBadRequest,
+		Detail:   "Invalid data",
+		Instance: "/api/v1/plans",
+		Code:     problems.CodeValidationFailed,
+		Errors: []problems.FieldError{{
+			Pointer: "/linkedPlanServices/1/linkedService/objectId",
+			Keyword: "required",
+			Message: "objectId is required",
+		}},
+	}
+	if !reflect.DeepEqual(problem, want) {
+		t.Errorf("problem = %+v, want %+v", problem, want)
+	}
+}
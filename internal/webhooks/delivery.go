@@ -0,0 +1,301 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"BigDataForge/internal/changefeed"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Deliveries wait in the sorted set webhook:queue, scored by the time they are
+// due, with their payload in webhook:delivery:<id>. Failed attempts are
+// rescheduled with exponential backoff; a subscription is disabled after
+// DisableAfter consecutive failed attempts.
+//
+// Requests are signed like this:
+//
+//	X-Webhook-Signature: t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>" with the subscription secret>
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+
+	queueKey    = "webhook:queue"
+	deliveryTTL = 7 * 24 * time.Hour
+)
+
+// Payload is the JSON body POSTed to subscribers.
+type Payload struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	PlanID    string          `json:"planId"`
+	Org       string          `json:"org"`
+	ETag      string          `json:"etag,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+type delivery struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscriptionId"`
+	EventID        string          `json:"eventId"`
+	EventType      string          `json:"eventType"`
+	Attempt        int             `json:"attempt"`
+	Body           json.RawMessage `json:"body"`
+}
+
+// Sign computes the signature header value for a body.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t + "."))
+	mac.Write(body)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatcher queues deliveries of plan events to matching subscriptions.
+type Dispatcher struct {
+	redisClient *redis.Client
+	store       *Store
+}
+
+func NewDispatcher(redisClient *redis.Client, store *Store) *Dispatcher {
+	return &Dispatcher{redisClient: redisClient, store: store}
+}
+
+// Dispatch queues the event for every active subscription of its org that selects its type.
+func (d *Dispatcher) Dispatch(ctx context.Context, event changefeed.Event) error {
+	subs, err := d.store.ListByOrg(ctx, event.Org)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(Payload{
+		ID:        event.ID,
+		Type:      event.Type,
+		PlanID:    event.PlanID,
+		Org:       event.Org,
+		ETag:      event.ETag,
+		Timestamp: event.Timestamp,
+		Data:      event.Document,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subs {
+		if !sub.Active || !sub.Wants(event.Type) {
+			continue
+		}
+		id, err := randomHex(12)
+		if err != nil {
+			return err
+		}
+		if err := d.schedule(ctx, delivery{
+			ID:             id,
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Attempt:        1,
+			Body:           body,
+		}, time.Now()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Dispatcher) schedule(ctx context.Context, item delivery, due time.Time) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	_, err = d.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, "webhook:delivery:"+item.ID, data, deliveryTTL)
+		pipe.ZAdd(ctx, queueKey, &redis.Z{Score: float64(due.UnixMilli()), Member: item.ID})
+		return nil
+	})
+	return err
+}
+
+// claimDue atomically leases the next due delivery by pushing its due time
+// past the lease, so several workers can share the queue and a delivery
+// claimed by a worker that crashed is retried once the lease runs out.
+var claimDue = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, 1)
+if #due == 0 then
+	return false
+end
+redis.call('ZADD', KEYS[1], ARGV[2], due[1])
+return due[1]
+`)
+
+const claimLease = time.Minute
+
+// Worker sends queued deliveries.
+type Worker struct {
+	Dispatcher   *Dispatcher
+	HTTPClient   *http.Client
+	MaxAttempts  int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	DisableAfter int
+	PollInterval time.Duration
+}
+
+func NewWorker(dispatcher *Dispatcher) *Worker {
+	return &Worker{
+		Dispatcher:   dispatcher,
+		HTTPClient:   NewHTTPClient(10 * time.Second),
+		MaxAttempts:  8,
+		BaseDelay:    5 * time.Second,
+		MaxDelay:     time.Hour,
+		DisableAfter: 20,
+		PollInterval: time.Second,
+	}
+}
+
+// Run delivers until ctx is cancelled. A delivery in flight is finished, so
+// that it is not sent again after its lease expires.
+func (w *Worker) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		delivered, err := w.deliverNext(context.WithoutCancel(ctx))
+		if err != nil {
+			slog.Error("Webhook worker error", "error", err)
+		}
+		if delivered {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(w.PollInterval):
+		}
+	}
+}
+
+// Helper to send one due delivery; reports whether there was one
+func (w *Worker) deliverNext(ctx context.Context) (bool, error) {
+	redisClient := w.Dispatcher.redisClient
+	now := time.Now()
+	id, err := claimDue.Run(ctx, redisClient, []string{queueKey}, now.UnixMilli(), now.Add(claimLease).UnixMilli()).Text()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	data, err := redisClient.Get(ctx, "webhook:delivery:"+id).Bytes()
+	if err == redis.Nil {
+		return true, w.finish(ctx, id) // expired
+	}
+	if err != nil {
+		return true, err
+	}
+	var item delivery
+	if err := json.Unmarshal(data, &item); err != nil {
+		return true, err
+	}
+
+	sub, err := w.Dispatcher.store.Get(ctx, item.SubscriptionID)
+	if errors.Is(err, ErrUnknownSubscription) || (err == nil && !sub.Active) {
+		return true, w.finish(ctx, id)
+	}
+	if err != nil {
+		return true, err
+	}
+
+	attempt := w.send(ctx, sub, item)
+	failures, err := w.Dispatcher.store.RecordAttempt(ctx, sub.ID, attempt)
+	if err != nil {
+		return true, err
+	}
+
+	switch {
+	case attempt.Success:
+		return true, w.finish(ctx, id)
+	case failures >= w.DisableAfter:
+		slog.WarnContext(ctx, "Disabling webhook after consecutive failures", "webhookId", sub.ID, "failures", failures)
+		if err := w.Dispatcher.store.Disable(ctx, sub.ID, fmt.Sprintf("disabled after %d consecutive failed deliveries", failures)); err != nil {
+			return true, err
+		}
+		return true, w.finish(ctx, id)
+	case item.Attempt >= w.MaxAttempts:
+		slog.WarnContext(ctx, "Giving up webhook delivery", "deliveryId", item.ID, "webhookId", sub.ID, "attempts", item.Attempt)
+		return true, w.finish(ctx, id)
+	}
+
+	item.Attempt++
+	return true, w.Dispatcher.schedule(ctx, item, time.Now().Add(w.backoff(item.Attempt)))
+}
+
+// Helper to drop a delivery from the queue
+func (w *Worker) finish(ctx context.Context, id string) error {
+	redisClient := w.Dispatcher.redisClient
+	_, err := redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, queueKey, id)
+		pipe.Del(ctx, "webhook:delivery:"+id)
+		return nil
+	})
+	return err
+}
+
+// Helper to compute the delay before an attempt: exponential with +-20% jitter
+func (w *Worker) backoff(attempt int) time.Duration {
+	delay := w.BaseDelay << (attempt - 2)
+	if delay > w.MaxDelay || delay <= 0 {
+		delay = w.MaxDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay)/5*2+1)) - delay/5
+	return delay + jitter
+}
+
+func (w *Worker) send(ctx context.Context, sub *Subscription, item delivery) Attempt {
+	attempt := Attempt{
+		DeliveryID: item.ID,
+		EventID:    item.EventID,
+		EventType:  item.EventType,
+		Attempt:    item.Attempt,
+		At:         time.Now().UTC(),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(item.Body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "BigDataForge-Webhooks/1.0")
+	req.Header.Set("X-Webhook-Id", item.ID)
+	req.Header.Set("X-Webhook-Event", item.EventType)
+	req.Header.Set(SignatureHeader, Sign(sub.Secret, time.Now(), item.Body))
+
+	start := time.Now()
+	res, err := w.HTTPClient.Do(req)
+	attempt.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer res.Body.Close()
+
+	attempt.StatusCode = res.StatusCode
+	attempt.Success = res.StatusCode >= 200 && res.StatusCode < 300
+	if !attempt.Success {
+		// Only the status is recorded: response bodies are the endpoint's, not the org's
+		attempt.Error = fmt.Sprintf("unexpected status %d", res.StatusCode)
+	}
+	return attempt
+}
@@ -0,0 +1,316 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"BigDataForge/internal/changefeed"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+// receiver is an httptest endpoint that answers with status and keeps the
+// requests it received.
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	status   int
+	requests []receivedRequest
+}
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newReceiver(t *testing.T, status int) *receiver {
+	t.Helper()
+	r := &receiver{status: status}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.requests = append(r.requests, receivedRequest{header: req.Header.Clone(), body: body})
+		if r.status == http.StatusFound {
+			http.Redirect(w, req, "http://169.254.169.254/latest/meta-data", r.status)
+			return
+		}
+		w.WriteHeader(r.status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) received() []receivedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedRequest(nil), r.requests...)
+}
+
+// Helper to set up a worker on miniredis whose client may reach loopback
+// receivers, and a subscription of org a.com for url
+func newTestWorker(t *testing.T, url string) (*Worker, *Subscription, *miniredis.Miniredis) {
+	t.Helper()
+	allowLoopback = true
+	t.Cleanup(func() { allowLoopback = false })
+
+	server := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+
+	store := NewStore(redisClient)
+	sub := &Subscription{URL: url, Org: "a.com", CreatedBy: "oidc:alice"}
+	if err := store.Create(context.Background(), sub); err != nil {
+		t.Fatal(err)
+	}
+	worker := NewWorker(NewDispatcher(redisClient, store))
+	worker.HTTPClient = NewHTTPClient(5 * time.Second)
+	return worker, sub, server
+}
+
+func dispatchEvent(t *testing.T, worker *Worker) {
+	t.Helper()
+	event := changefeed.Event{ID: "1-0", Type: "create", PlanID: "p1", Org: "a.com", ETag: `"e1"`, Timestamp: time.Now().UTC(), Document: json.RawMessage(`{"objectId":"p1"}`)}
+	if err := worker.Dispatcher.Dispatch(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Helper to make every queued delivery due again
+func makeDue(t *testing.T, server *miniredis.Miniredis) {
+	t.Helper()
+	members, err := server.ZMembers(queueKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, member := range members {
+		server.ZAdd(queueKey, 0, member)
+	}
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"1-0"}`)
+	timestamp := time.Unix(1700000000, 0)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := "t=1700000000,v1=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := Sign("secret", timestamp, body); got != want {
+		t.Errorf("Sign = %s, want %s", got, want)
+	}
+	if Sign("other", timestamp, body) == want {
+		t.Error("signatures of different secrets match")
+	}
+}
+
+func TestWorkerDeliversSignedPayload(t *testing.T) {
+	endpoint := newReceiver(t, http.StatusNoContent)
+	worker, sub, server := newTestWorker(t, endpoint.URL)
+	dispatchEvent(t, worker)
+
+	delivered, err := worker.deliverNext(context.Background())
+	if err != nil || !delivered {
+		t.Fatalf("deliverNext = %v, %v", delivered, err)
+	}
+
+	requests := endpoint.received()
+	if len(requests) != 1 {
+		t.Fatalf("receiver got %d requests, want 1", len(requests))
+	}
+	request := requests[0]
+	signature := request.header.Get(SignatureHeader)
+	timestamp, _, _ := strings.Cut(strings.TrimPrefix(signature, "t="), ",")
+	seconds, _ := strconv.ParseInt(timestamp, 10, 64)
+	if signature != Sign(sub.Secret, time.Unix(seconds, 0), request.body) {
+		t.Errorf("signature %s does not match the body", signature)
+	}
+	var payload Payload
+	if err := json.Unmarshal(request.body, &payload); err != nil || payload.PlanID != "p1" || string(payload.Data) != `{"objectId":"p1"}` {
+		t.Errorf("payload = %s (%v)", request.body, err)
+	}
+	if request.header.Get("X-Webhook-Event") != "create" {
+		t.Errorf("X-Webhook-Event = %q, want create", request.header.Get("X-Webhook-Event"))
+	}
+
+	if members, _ := server.ZMembers(queueKey); len(members) != 0 {
+		t.Errorf("queue still holds %v after a successful delivery", members)
+	}
+	attempts, err := worker.Dispatcher.store.Deliveries(context.Background(), sub.ID, 10)
+	if err != nil || len(attempts) != 1 || !attempts[0].Success || attempts[0].StatusCode != http.StatusNoContent {
+		t.Errorf("delivery log = %+v (%v), want one successful attempt", attempts, err)
+	}
+}
+
+func TestWorkerRetriesWithBackoff(t *testing.T) {
+	endpoint := newReceiver(t, http.StatusServiceUnavailable)
+	worker, sub, server := newTestWorker(t, endpoint.URL)
+	worker.MaxAttempts = 2
+	dispatchEvent(t, worker)
+
+	before := time.Now()
+	if _, err := worker.deliverNext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	members, _ := server.ZMembers(queueKey)
+	if len(members) != 1 {
+		t.Fatalf("queue = %v, want the rescheduled delivery", members)
+	}
+	score, _ := server.ZScore(queueKey, members[0])
+	due := time.UnixMilli(int64(score))
+	if min, max := before.Add(worker.BaseDelay*4/5), time.Now().Add(worker.BaseDelay*6/5); due.Before(min) || due.After(max) {
+		t.Errorf("retry due at %s, want between %s and %s", due, min, max)
+	}
+
+	// The second attempt is the last one
+	makeDue(t, server)
+	if _, err := worker.deliverNext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if members, _ := server.ZMembers(queueKey); len(members) != 0 {
+		t.Errorf("queue = %v after the last attempt, want it empty", members)
+	}
+	attempts, _ := worker.Dispatcher.store.Deliveries(context.Background(), sub.ID, 10)
+	if len(attempts) != 2 || attempts[0].Attempt != 2 || attempts[0].StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("delivery log = %+v, want two failed attempts", attempts)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	worker := &Worker{BaseDelay: 5 * time.Second, MaxDelay: time.Hour}
+	for _, tc := range []struct {
+		attempt int
+		delay   time.Duration
+	}{
+		{2, 5 * time.Second},
+		{3, 10 * time.Second},
+		{5, 40 * time.Second},
+		{20, time.Hour},
+		{100, time.Hour},
+	} {
+		for i := 0; i < 20; i++ {
+			got := worker.backoff(tc.attempt)
+			if got < tc.delay*4/5 || got > tc.delay*6/5 {
+				t.Errorf("backoff(%d) = %s, want %s +-20%%", tc.attempt, got, tc.delay)
+				break
+			}
+		}
+	}
+}
+
+func TestWorkerDisablesFailingSubscription(t *testing.T) {
+	endpoint := newReceiver(t, http.StatusInternalServerError)
+	worker, sub, server := newTestWorker(t, endpoint.URL)
+	worker.DisableAfter = 2
+	dispatchEvent(t, worker)
+
+	for i := 0; i < 2; i++ {
+		makeDue(t, server)
+		if _, err := worker.deliverNext(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stored, err := worker.Dispatcher.store.Get(context.Background(), sub.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Active || stored.DisabledReason == "" {
+		t.Errorf("subscription = %+v, want it disabled with a reason", stored)
+	}
+	if members, _ := server.ZMembers(queueKey); len(members) != 0 {
+		t.Errorf("queue = %v after disabling, want it empty", members)
+	}
+
+	// Later events are not queued for the disabled subscription
+	dispatchEvent(t, worker)
+	if members, _ := server.ZMembers(queueKey); len(members) != 0 {
+		t.Errorf("queue = %v, want no deliveries to a disabled subscription", members)
+	}
+}
+
+func TestWorkerDoesNotFollowRedirects(t *testing.T) {
+	endpoint := newReceiver(t, http.StatusFound)
+	worker, sub, _ := newTestWorker(t, endpoint.URL)
+	dispatchEvent(t, worker)
+
+	if _, err := worker.deliverNext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	attempts, _ := worker.Dispatcher.store.Deliveries(context.Background(), sub.ID, 10)
+	if len(attempts) != 1 || attempts[0].Success || attempts[0].StatusCode != http.StatusFound {
+		t.Errorf("delivery log = %+v, want one failed attempt with the redirect status", attempts)
+	}
+}
+
+func TestHTTPClientRefusesInternalAddresses(t *testing.T) {
+	endpoint := newReceiver(t, http.StatusNoContent)
+
+	_, err := NewHTTPClient(time.Second).Post(endpoint.URL, "application/json", strings.NewReader("{}"))
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("delivery to %s: error = %v, want ErrForbiddenAddress", endpoint.URL, err)
+	}
+	if len(endpoint.received()) != 0 {
+		t.Error("the receiver on loopback was reached")
+	}
+}
+
+func TestForbiddenAddress(t *testing.T) {
+	for _, tc := range []struct {
+		addr      string
+		forbidden bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"::ffff:127.0.0.1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"fe80::1", true},
+		{"fc00::1", true},
+		{"100.64.0.1", true},
+		{"0.0.0.0", true},
+		{"224.0.0.1", true},
+		{"93.184.216.34", false},
+		{"2606:2800:220:1::1", false},
+	} {
+		if got := forbiddenAddress(netip.MustParseAddr(tc.addr)); got != tc.forbidden {
+			t.Errorf("forbiddenAddress(%s) = %v, want %v", tc.addr, got, tc.forbidden)
+		}
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	for _, tc := range []struct {
+		host      string
+		forbidden bool
+	}{
+		{"localhost", true},
+		{"LOCALHOST.", true},
+		{"api.localhost", true},
+		{"127.0.0.1", true},
+		{"[::1]", true},
+		{"10.0.0.1", true},
+		{"example.com", false},
+		{"93.184.216.34", false},
+	} {
+		if err := CheckHost(tc.host); (err != nil) != tc.forbidden {
+			t.Errorf("CheckHost(%q) = %v, want forbidden %v", tc.host, err, tc.forbidden)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package webhooks
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Webhook endpoints are chosen by org admins, so deliveries must not reach the
+// deployment's own network: the client only connects to public addresses,
+// checked on the address actually dialed after DNS resolution so that a name
+// resolving to an internal address is refused too. Redirects are not followed,
+// and response bodies are never recorded.
+
+// ErrForbiddenAddress is returned for endpoints on loopback, private,
+// link-local or otherwise non-public addresses.
+var ErrForbiddenAddress = errors.New("webhook endpoint address is not public")
+
+// Carrier-grade NAT, which IsPrivate does not cover
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// allowLoopback lets tests deliver to httptest receivers, which listen on loopback
+var allowLoopback = false
+
+// Helper to report addresses deliveries must not connect to
+func forbiddenAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return (addr.IsLoopback() && !allowLoopback) || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() || sharedAddressSpace.Contains(addr)
+}
+
+// CheckHost rejects endpoint hosts that are non-public IP literals or
+// localhost. Names are checked again on every connection, once resolved.
+func CheckHost(host string) error {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrForbiddenAddress
+	}
+	if addr, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil && forbiddenAddress(addr) {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
+
+// Helper to refuse connections to non-public addresses, called by the dialer
+// with the resolved address
+func controlDial(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("unexpected dial address %q: %w", address, err)
+	}
+	if forbiddenAddress(addrPort.Addr()) {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
+
+// NewHTTPClient returns the client of deliveries. It ignores proxy settings,
+// which would hide the address dialed, and returns redirects as responses.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second, Control: controlDial}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
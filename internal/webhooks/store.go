@@ -0,0 +1,249 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Redis layout:
+//
+//	webhook:<id>             hash with the subscription
+//	webhooks                 set of all subscription IDs
+//	webhooks:org:<org>       set of subscription IDs per org, used for dispatch
+//	webhook:<id>:deliveries  list of the latest delivery attempts, newest first
+
+const deliveryLogLength = 100
+
+var ErrUnknownSubscription = errors.New("unknown webhook subscription")
+
+// EventTypes are the plan events a subscription can select.
+var EventTypes = []string{"create", "update", "patch", "delete"}
+
+// Subscription is a partner endpoint receiving plan events of one org.
+type Subscription struct {
+	ID                  string    `json:"id"`
+	URL                 string    `json:"url"`
+	Events              []string  `json:"events"` // empty selects every event type
+	Org                 string    `json:"org"`
+	Secret              string    `json:"secret,omitempty"`
+	Active              bool      `json:"active"`
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	DisabledReason      string    `json:"disabledReason,omitempty"`
+	CreatedBy           string    `json:"createdBy"`
+	CreatedAt           time.Time `json:"createdAt"`
+}
+
+// Wants reports whether the subscription receives an event type.
+func (s *Subscription) Wants(eventType string) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, selected := range s.Events {
+		if selected == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Attempt is one entry of a subscription's delivery log.
+type Attempt struct {
+	DeliveryID string    `json:"deliveryId"`
+	EventID    string    `json:"eventId"`
+	EventType  string    `json:"eventType"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Success    bool      `json:"success"`
+	DurationMs int64     `json:"durationMs"`
+	At         time.Time `json:"at"`
+}
+
+// Store keeps webhook subscriptions and their delivery logs in Redis.
+type Store struct {
+	redisClient *redis.Client
+}
+
+func NewStore(redisClient *redis.Client) *Store {
+	return &Store{redisClient: redisClient}
+}
+
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Create stores a new active subscription, generating its ID and, when
+// missing, its signing secret.
+func (s *Store) Create(ctx context.Context, sub *Subscription) error {
+	id, err := randomHex(8)
+	if err != nil {
+		return err
+	}
+	if sub.Secret == "" {
+		if sub.Secret, err = randomHex(32); err != nil {
+			return err
+		}
+	}
+	sub.ID, sub.Active, sub.CreatedAt = id, true, time.Now().UTC()
+
+	_, err = s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, "webhook:"+sub.ID, map[string]interface{}{
+			"url":       sub.URL,
+			"events":    strings.Join(sub.Events, " "),
+			"org":       sub.Org,
+			"secret":    sub.Secret,
+			"active":    "1",
+			"failures":  0,
+			"createdBy": sub.CreatedBy,
+			"createdAt": sub.CreatedAt.Format(time.RFC3339),
+		})
+		pipe.SAdd(ctx, "webhooks", sub.ID)
+		pipe.SAdd(ctx, "webhooks:org:"+sub.Org, sub.ID)
+		return nil
+	})
+	return err
+}
+
+// Get returns a subscription including its secret.
+func (s *Store) Get(ctx context.Context, id string) (*Subscription, error) {
+	fields, err := s.redisClient.HGetAll(ctx, "webhook:"+id).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, ErrUnknownSubscription
+	}
+	failures, _ := strconv.Atoi(fields["failures"])
+	createdAt, _ := time.Parse(time.RFC3339, fields["createdAt"])
+	return &Subscription{
+		ID:                  id,
+		URL:                 fields["url"],
+		Events:              strings.Fields(fields["events"]),
+		Org:                 fields["org"],
+		Secret:              fields["secret"],
+		Active:              fields["active"] == "1",
+		ConsecutiveFailures: failures,
+		DisabledReason:      fields["disabledReason"],
+		CreatedBy:           fields["createdBy"],
+		CreatedAt:           createdAt,
+	}, nil
+}
+
+func (s *Store) getAll(ctx context.Context, ids []string) ([]*Subscription, error) {
+	subs := make([]*Subscription, 0, len(ids))
+	for _, id := range ids {
+		sub, err := s.Get(ctx, id)
+		if errors.Is(err, ErrUnknownSubscription) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
+
+// List returns every subscription.
+func (s *Store) List(ctx context.Context) ([]*Subscription, error) {
+	ids, err := s.redisClient.SMembers(ctx, "webhooks").Result()
+	if err != nil {
+		return nil, err
+	}
+	return s.getAll(ctx, ids)
+}
+
+// ListByOrg returns the subscriptions of one org.
+func (s *Store) ListByOrg(ctx context.Context, org string) ([]*Subscription, error) {
+	ids, err := s.redisClient.SMembers(ctx, "webhooks:org:"+org).Result()
+	if err != nil {
+		return nil, err
+	}
+	return s.getAll(ctx, ids)
+}
+
+// Delete removes a subscription and its delivery log.
+func (s *Store) Delete(ctx context.Context, id string) error {
+	sub, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	_, err = s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, "webhook:"+id, "webhook:"+id+":deliveries")
+		pipe.SRem(ctx, "webhooks", id)
+		pipe.SRem(ctx, "webhooks:org:"+sub.Org, id)
+		return nil
+	})
+	return err
+}
+
+// Enable reactivates a subscription and clears its failure count.
+func (s *Store) Enable(ctx context.Context, id string) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	_, err := s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, "webhook:"+id, "active", "1", "failures", 0)
+		pipe.HDel(ctx, "webhook:"+id, "disabledReason")
+		return nil
+	})
+	return err
+}
+
+// Disable stops deliveries to a subscription.
+func (s *Store) Disable(ctx context.Context, id, reason string) error {
+	return s.redisClient.HSet(ctx, "webhook:"+id, "active", "0", "disabledReason", reason).Err()
+}
+
+// RecordAttempt appends to the delivery log and updates the failure count,
+// returning the number of consecutive failures.
+func (s *Store) RecordAttempt(ctx context.Context, subscriptionID string, attempt Attempt) (int, error) {
+	data, err := json.Marshal(attempt)
+	if err != nil {
+		return 0, err
+	}
+
+	var failures *redis.IntCmd
+	_, err = s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, "webhook:"+subscriptionID+":deliveries", data)
+		pipe.LTrim(ctx, "webhook:"+subscriptionID+":deliveries", 0, deliveryLogLength-1)
+		if attempt.Success {
+			pipe.HSet(ctx, "webhook:"+subscriptionID, "failures", 0)
+		} else {
+			failures = pipe.HIncrBy(ctx, "webhook:"+subscriptionID, "failures", 1)
+		}
+		return nil
+	})
+	if err != nil || failures == nil {
+		return 0, err
+	}
+	return int(failures.Val()), nil
+}
+
+// Deliveries returns the latest delivery attempts, newest first.
+func (s *Store) Deliveries(ctx context.Context, subscriptionID string, limit int64) ([]Attempt, error) {
+	entries, err := s.redisClient.LRange(ctx, "webhook:"+subscriptionID+":deliveries", 0, limit-1).Result()
+	if err != nil {
+		return nil, err
+	}
+	attempts := make([]Attempt, 0, len(entries))
+	for _, entry := range entries {
+		var attempt Attempt
+		if err := json.Unmarshal([]byte(entry), &attempt); err == nil {
+			attempts = append(attempts, attempt)
+		}
+	}
+	return attempts, nil
+}